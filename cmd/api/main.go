@@ -27,16 +27,59 @@ func main() {
 	rateLimiter := middleware.NewRateLimiter(cfg.RateLimitRequests, cfg.RateLimitWindow)
 	defer rateLimiter.Stop()
 
-	scClient := scclient.New(cfg.AuthToken, cfg.ClientID, cfg.RequestTimeout)
-	handler := handlers.New(cfg, scClient, rateLimiter)
+	logger := handlers.NewLogger(cfg.LogFile)
+
+	// Without a configured client_id, discovery is the only way to get one.
+	discoverClientID := cfg.ClientIDDiscovery || cfg.ClientID == ""
+
+	opts := []scclient.Option{
+		scclient.WithBaseURL(cfg.APIBaseURL),
+		scclient.WithWebURL(cfg.WebURL),
+		scclient.WithUserAgent(cfg.UserAgent),
+		scclient.WithClientIDDiscovery(discoverClientID),
+		scclient.WithLogger(logger, cfg.Debug),
+		scclient.WithCredentials(additionalCredentials(cfg)...),
+		scclient.WithCredentialCooldown(cfg.CredentialCooldown),
+		scclient.WithRetryPolicy(scclient.RetryPolicy{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay,
+			MaxDelay:    cfg.RetryMaxDelay,
+		}),
+		scclient.WithBreaker(scclient.BreakerConfig{
+			Window:         cfg.BreakerWindow,
+			MinRequests:    cfg.BreakerMinRequests,
+			FailureRatio:   cfg.BreakerFailureRatio,
+			SlowCall:       cfg.BreakerSlowCall,
+			OpenTimeout:    cfg.BreakerOpenTimeout,
+			HalfOpenProbes: 1,
+		}),
+	}
+	oauthEnabled := cfg.OAuthRefreshToken != "" || cfg.TokenFile != ""
+	if oauthEnabled {
+		opts = append(opts, scclient.WithOAuth(scclient.OAuthConfig{
+			TokenURL:     cfg.OAuthTokenURL,
+			ClientID:     cfg.OAuthClientID,
+			ClientSecret: cfg.OAuthClientSecret,
+			RefreshToken: cfg.OAuthRefreshToken,
+			TokenFile:    cfg.TokenFile,
+		}))
+	}
+	scClient := scclient.New(cfg.AuthToken, cfg.ClientID, cfg.RequestTimeout, opts...)
+	handler := handlers.New(cfg, scClient, rateLimiter, logger)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	valid, errMsg := scClient.ValidateToken(ctx)
-	if valid {
-		handler.Logger.Println("SoundCloud auth_token is valid!")
-	} else {
-		handler.Logger.Printf("SoundCloud auth_token is invalid: %s", errMsg)
+	if discoverClientID {
+		if _, err := scClient.RefreshClientID(ctx); err != nil {
+			handler.Logger.Printf("SoundCloud client_id discovery failed: %v", err)
+		}
+	}
+	for _, cs := range scClient.CheckCredentials(ctx) {
+		if cs.Valid {
+			handler.Logger.Printf("SoundCloud credential %d is valid!", cs.Index)
+		} else {
+			handler.Logger.Printf("SoundCloud credential %d is invalid: %s", cs.Index, cs.Error)
+		}
 	}
 
 	mux := http.NewServeMux()
@@ -53,6 +96,32 @@ func main() {
 			handler.NotFoundHandler(w, r)
 		}
 	})
+	// The middleware charges a batch's first track, so invalid batches count
+	// as well; the handlers charge the remaining tracks themselves.
+	mux.HandleFunc("/soundcloud/stream-url/batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handler.NotFoundHandler(w, r)
+			return
+		}
+		rateLimitMiddleware(handler.BatchStreamHandler)(w, r)
+	})
+	mux.HandleFunc("/soundcloud/stream-url/batch/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handler.NotFoundHandler(w, r)
+			return
+		}
+		rateLimitMiddleware(handler.BatchStreamEventsHandler)(w, r)
+	})
+	mux.HandleFunc("/soundcloud/track", getOnly(handler, rateLimitMiddleware(handler.TrackHandler)))
+	mux.HandleFunc("/soundcloud/transcodings", getOnly(handler, rateLimitMiddleware(handler.TranscodingsHandler)))
+	mux.HandleFunc("/soundcloud/playlist", getOnly(handler, rateLimitMiddleware(handler.PlaylistHandler)))
+	mux.HandleFunc("/soundcloud/user", getOnly(handler, rateLimitMiddleware(handler.UserHandler)))
+	mux.HandleFunc("/soundcloud/user/tracks", getOnly(handler, rateLimitMiddleware(handler.UserTracksHandler)))
+	mux.HandleFunc("/soundcloud/user/likes", getOnly(handler, rateLimitMiddleware(handler.UserLikesHandler)))
+	mux.HandleFunc("/soundcloud/user/reposts", getOnly(handler, rateLimitMiddleware(handler.UserRepostsHandler)))
+	mux.HandleFunc("/soundcloud/comments", getOnly(handler, rateLimitMiddleware(handler.CommentsHandler)))
+	mux.HandleFunc("/soundcloud/related", getOnly(handler, rateLimitMiddleware(handler.RelatedHandler)))
+	mux.HandleFunc("/soundcloud/search", getOnly(handler, rateLimitMiddleware(handler.SearchHandler)))
 	mux.HandleFunc("/", handler.NotFoundHandler)
 
 	server := &http.Server{
@@ -87,6 +156,10 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if oauthEnabled {
+		go scClient.RunTokenRefresh(ctx)
+	}
+
 	go func() {
 		handler.Logger.Printf("Server starting on :%s", actualPort)
 		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
@@ -106,3 +179,31 @@ func main() {
 		handler.Logger.Println("Server shutdown completed")
 	}
 }
+
+// getOnly routes GET requests to next and everything else to the not-found
+// handler, matching the method handling of /soundcloud/stream-url.
+func getOnly(h *handlers.Handlers, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			h.NotFoundHandler(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// additionalCredentials pairs AUTH_TOKENS with CLIENT_IDS by position. Missing
+// client IDs fall back to CLIENT_ID.
+func additionalCredentials(cfg *config.Config) []scclient.Credential {
+	creds := make([]scclient.Credential, max(len(cfg.AuthTokens), len(cfg.ClientIDs)))
+	for i := range creds {
+		creds[i].ClientID = cfg.ClientID
+		if i < len(cfg.AuthTokens) {
+			creds[i].AuthToken = cfg.AuthTokens[i]
+		}
+		if i < len(cfg.ClientIDs) && cfg.ClientIDs[i] != "" {
+			creds[i].ClientID = cfg.ClientIDs[i]
+		}
+	}
+	return creds
+}
@@ -0,0 +1,65 @@
+package captions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Cue is a piece of text shown between Start and End.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// WriteVTT writes cues as a WebVTT document.
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		fmt.Fprintf(bw, "\n%s --> %s\n%s\n", timestamp(c.Start, '.'), timestamp(c.End, '.'), vttEscape(cleanText(c.Text)))
+	}
+	return bw.Flush()
+}
+
+// WriteSRT writes cues as a SubRip document.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n", i+1, timestamp(c.Start, ','), timestamp(c.End, ','), cleanText(c.Text))
+	}
+	return bw.Flush()
+}
+
+// timestamp formats d as HH:MM:SS followed by sep and milliseconds.
+func timestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// cleanText drops empty lines, which would otherwise end the cue early in both
+// formats.
+func cleanText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var vttReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func vttEscape(s string) string {
+	return vttReplacer.Replace(s)
+}
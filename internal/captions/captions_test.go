@@ -0,0 +1,39 @@
+package captions
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCues = []Cue{
+	{Start: 1500 * time.Millisecond, End: 5500 * time.Millisecond, Text: "dj: drop <3"},
+	{Start: time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond, End: time.Hour + 2*time.Minute + 7*time.Second, Text: "first line\n\nsecond line"},
+}
+
+func TestWriteVTT(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteVTT(&sb, testCues); err != nil {
+		t.Fatalf("WriteVTT returned error: %v", err)
+	}
+
+	want := "WEBVTT\n" +
+		"\n00:00:01.500 --> 00:00:05.500\ndj: drop &lt;3\n" +
+		"\n01:02:03.004 --> 01:02:07.000\nfirst line\nsecond line\n"
+	if sb.String() != want {
+		t.Fatalf("WriteVTT output:\n%q\nwant:\n%q", sb.String(), want)
+	}
+}
+
+func TestWriteSRT(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteSRT(&sb, testCues); err != nil {
+		t.Fatalf("WriteSRT returned error: %v", err)
+	}
+
+	want := "1\n00:00:01,500 --> 00:00:05,500\ndj: drop <3\n" +
+		"\n2\n01:02:03,004 --> 01:02:07,000\nfirst line\nsecond line\n"
+	if sb.String() != want {
+		t.Fatalf("WriteSRT output:\n%q\nwant:\n%q", sb.String(), want)
+	}
+}
@@ -9,15 +9,38 @@ import (
 )
 
 type Config struct {
-	AuthToken         string
-	ClientID          string
-	RateLimitRequests int
-	RateLimitWindow   time.Duration
-	RequestTimeout    time.Duration
-	MaxTrackURLLen    int
-	LogFile           string
-	Port              string
-	Debug             bool
+	AuthToken           string
+	ClientID            string
+	APIBaseURL          string
+	WebURL              string
+	UserAgent           string
+	ClientIDDiscovery   bool
+	OAuthClientID       string
+	OAuthClientSecret   string
+	OAuthRefreshToken   string
+	OAuthTokenURL       string
+	TokenFile           string
+	AuthTokens          []string
+	ClientIDs           []string
+	CredentialCooldown  time.Duration
+	RetryMaxAttempts    int
+	RetryBaseDelay      time.Duration
+	RetryMaxDelay       time.Duration
+	BreakerWindow       time.Duration
+	BreakerMinRequests  int
+	BreakerFailureRatio float64
+	BreakerSlowCall     time.Duration
+	BreakerOpenTimeout  time.Duration
+	BatchMaxItems       int
+	BatchConcurrency    int
+	BatchItemTimeout    time.Duration
+	RateLimitRequests   int
+	RateLimitWindow     time.Duration
+	RequestTimeout      time.Duration
+	MaxTrackURLLen      int
+	LogFile             string
+	Port                string
+	Debug               bool
 }
 
 // LoadEnvFile loads KEY=VALUE pairs from a .env file.
@@ -65,15 +88,38 @@ func LoadEnvFile(path string) error {
 
 func Load() *Config {
 	return &Config{
-		AuthToken:         getEnv("AUTH_TOKEN", ""),
-		ClientID:          getEnv("CLIENT_ID", ""),
-		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
-		RateLimitWindow:   getEnvAsDuration("RATE_LIMIT_WINDOW", 3600*time.Second),
-		RequestTimeout:    getEnvAsDuration("REQUEST_TIMEOUT", 30*time.Second),
-		MaxTrackURLLen:    getEnvAsInt("MAX_TRACK_URL_LEN", 500),
-		LogFile:           getEnv("LOG_FILE", "SC_API.log"),
-		Port:              getEnv("PORT", "5000"),
-		Debug:             getEnvAsBool("DEBUG", false),
+		AuthToken:           getEnv("AUTH_TOKEN", ""),
+		ClientID:            getEnv("CLIENT_ID", ""),
+		APIBaseURL:          getEnv("SC_API_BASE_URL", "https://api-v2.soundcloud.com"),
+		WebURL:              getEnv("SC_WEB_URL", "https://soundcloud.com"),
+		UserAgent:           getEnv("SC_USER_AGENT", "Go-http-client/1.1"),
+		ClientIDDiscovery:   getEnvAsBool("CLIENT_ID_DISCOVERY", false),
+		OAuthClientID:       getEnv("OAUTH_CLIENT_ID", getEnv("CLIENT_ID", "")),
+		OAuthClientSecret:   getEnv("OAUTH_CLIENT_SECRET", ""),
+		OAuthRefreshToken:   getEnv("OAUTH_REFRESH_TOKEN", ""),
+		OAuthTokenURL:       getEnv("OAUTH_TOKEN_URL", "https://secure.soundcloud.com/oauth/token"),
+		TokenFile:           getEnv("TOKEN_FILE", ""),
+		AuthTokens:          getEnvAsList("AUTH_TOKENS"),
+		ClientIDs:           getEnvAsList("CLIENT_IDS"),
+		CredentialCooldown:  getEnvAsDuration("CREDENTIAL_COOLDOWN", 60*time.Second),
+		RetryMaxAttempts:    getEnvAsInt("RETRY_MAX_ATTEMPTS", 3),
+		RetryBaseDelay:      getEnvAsDuration("RETRY_BASE_DELAY", 250*time.Millisecond),
+		RetryMaxDelay:       getEnvAsDuration("RETRY_MAX_DELAY", 5*time.Second),
+		BreakerWindow:       getEnvAsDuration("BREAKER_WINDOW", 30*time.Second),
+		BreakerMinRequests:  getEnvAsInt("BREAKER_MIN_REQUESTS", 10),
+		BreakerFailureRatio: getEnvAsFloat("BREAKER_FAILURE_RATIO", 0.5),
+		BreakerSlowCall:     getEnvAsDuration("BREAKER_SLOW_CALL", 10*time.Second),
+		BreakerOpenTimeout:  getEnvAsDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		BatchMaxItems:       getEnvAsInt("BATCH_MAX_ITEMS", 50),
+		BatchConcurrency:    getEnvAsInt("BATCH_CONCURRENCY", 4),
+		BatchItemTimeout:    getEnvAsDuration("BATCH_ITEM_TIMEOUT", 10*time.Second),
+		RateLimitRequests:   getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
+		RateLimitWindow:     getEnvAsDuration("RATE_LIMIT_WINDOW", 3600*time.Second),
+		RequestTimeout:      getEnvAsDuration("REQUEST_TIMEOUT", 30*time.Second),
+		MaxTrackURLLen:      getEnvAsInt("MAX_TRACK_URL_LEN", 500),
+		LogFile:             getEnv("LOG_FILE", "SC_API.log"),
+		Port:                getEnv("PORT", "5000"),
+		Debug:               getEnvAsBool("DEBUG", false),
 	}
 }
 
@@ -93,6 +139,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -110,3 +165,17 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsList splits a comma-separated variable, keeping empty entries so
+// that positions in paired lists line up.
+func getEnvAsList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
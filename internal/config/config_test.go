@@ -56,6 +56,29 @@ INVALID_LINE
 func TestLoad_UsesEnvValues(t *testing.T) {
 	t.Setenv("AUTH_TOKEN", "auth-from-env")
 	t.Setenv("CLIENT_ID", "client-from-env")
+	t.Setenv("SC_API_BASE_URL", "http://127.0.0.1:9000")
+	t.Setenv("SC_USER_AGENT", "test-agent/1.0")
+	t.Setenv("SC_WEB_URL", "http://127.0.0.1:9001")
+	t.Setenv("CLIENT_ID_DISCOVERY", "true")
+	t.Setenv("OAUTH_CLIENT_ID", "oauth-client")
+	t.Setenv("OAUTH_CLIENT_SECRET", "secret")
+	t.Setenv("OAUTH_REFRESH_TOKEN", "refresh")
+	t.Setenv("OAUTH_TOKEN_URL", "http://127.0.0.1:9002/oauth/token")
+	t.Setenv("TOKEN_FILE", "token.json")
+	t.Setenv("AUTH_TOKENS", "second, third")
+	t.Setenv("CLIENT_IDS", "cid-2,")
+	t.Setenv("CREDENTIAL_COOLDOWN", "90s")
+	t.Setenv("RETRY_MAX_ATTEMPTS", "5")
+	t.Setenv("RETRY_BASE_DELAY", "100ms")
+	t.Setenv("RETRY_MAX_DELAY", "2s")
+	t.Setenv("BREAKER_WINDOW", "1m")
+	t.Setenv("BREAKER_MIN_REQUESTS", "20")
+	t.Setenv("BREAKER_FAILURE_RATIO", "0.25")
+	t.Setenv("BREAKER_SLOW_CALL", "3s")
+	t.Setenv("BREAKER_OPEN_TIMEOUT", "45s")
+	t.Setenv("BATCH_MAX_ITEMS", "100")
+	t.Setenv("BATCH_CONCURRENCY", "8")
+	t.Setenv("BATCH_ITEM_TIMEOUT", "5s")
 	t.Setenv("RATE_LIMIT_REQUESTS", "42")
 	t.Setenv("RATE_LIMIT_WINDOW", "2m")
 	t.Setenv("REQUEST_TIMEOUT", "15s")
@@ -74,6 +97,68 @@ func TestLoad_UsesEnvValues(t *testing.T) {
 		t.Fatalf("ClientID = %q, want %q", cfg.ClientID, "client-from-env")
 	}
 
+	if cfg.APIBaseURL != "http://127.0.0.1:9000" {
+		t.Fatalf("APIBaseURL = %q, want %q", cfg.APIBaseURL, "http://127.0.0.1:9000")
+	}
+
+	if cfg.UserAgent != "test-agent/1.0" {
+		t.Fatalf("UserAgent = %q, want %q", cfg.UserAgent, "test-agent/1.0")
+	}
+
+	if cfg.WebURL != "http://127.0.0.1:9001" {
+		t.Fatalf("WebURL = %q, want %q", cfg.WebURL, "http://127.0.0.1:9001")
+	}
+
+	if !cfg.ClientIDDiscovery {
+		t.Fatal("ClientIDDiscovery = false, want true")
+	}
+
+	if cfg.OAuthClientID != "oauth-client" {
+		t.Fatalf("OAuthClientID = %q, want %q", cfg.OAuthClientID, "oauth-client")
+	}
+
+	if cfg.OAuthClientSecret != "secret" {
+		t.Fatalf("OAuthClientSecret = %q, want %q", cfg.OAuthClientSecret, "secret")
+	}
+
+	if cfg.OAuthRefreshToken != "refresh" {
+		t.Fatalf("OAuthRefreshToken = %q, want %q", cfg.OAuthRefreshToken, "refresh")
+	}
+
+	if cfg.OAuthTokenURL != "http://127.0.0.1:9002/oauth/token" {
+		t.Fatalf("OAuthTokenURL = %q, want %q", cfg.OAuthTokenURL, "http://127.0.0.1:9002/oauth/token")
+	}
+
+	if cfg.TokenFile != "token.json" {
+		t.Fatalf("TokenFile = %q, want %q", cfg.TokenFile, "token.json")
+	}
+
+	if len(cfg.AuthTokens) != 2 || cfg.AuthTokens[0] != "second" || cfg.AuthTokens[1] != "third" {
+		t.Fatalf("AuthTokens = %q, want [second third]", cfg.AuthTokens)
+	}
+
+	if len(cfg.ClientIDs) != 2 || cfg.ClientIDs[0] != "cid-2" || cfg.ClientIDs[1] != "" {
+		t.Fatalf("ClientIDs = %q, want [cid-2 \"\"]", cfg.ClientIDs)
+	}
+
+	if cfg.CredentialCooldown != 90*time.Second {
+		t.Fatalf("CredentialCooldown = %s, want %s", cfg.CredentialCooldown, 90*time.Second)
+	}
+
+	if cfg.RetryMaxAttempts != 5 || cfg.RetryBaseDelay != 100*time.Millisecond || cfg.RetryMaxDelay != 2*time.Second {
+		t.Fatalf("retry settings = %d/%s/%s, want 5/100ms/2s", cfg.RetryMaxAttempts, cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+
+	if cfg.BreakerWindow != time.Minute || cfg.BreakerMinRequests != 20 || cfg.BreakerFailureRatio != 0.25 ||
+		cfg.BreakerSlowCall != 3*time.Second || cfg.BreakerOpenTimeout != 45*time.Second {
+		t.Fatalf("breaker settings = %s/%d/%v/%s/%s, want 1m/20/0.25/3s/45s",
+			cfg.BreakerWindow, cfg.BreakerMinRequests, cfg.BreakerFailureRatio, cfg.BreakerSlowCall, cfg.BreakerOpenTimeout)
+	}
+
+	if cfg.BatchMaxItems != 100 || cfg.BatchConcurrency != 8 || cfg.BatchItemTimeout != 5*time.Second {
+		t.Fatalf("batch settings = %d/%d/%s, want 100/8/5s", cfg.BatchMaxItems, cfg.BatchConcurrency, cfg.BatchItemTimeout)
+	}
+
 	if cfg.RateLimitRequests != 42 {
 		t.Fatalf("RateLimitRequests = %d, want %d", cfg.RateLimitRequests, 42)
 	}
@@ -106,6 +191,29 @@ func TestLoad_UsesEnvValues(t *testing.T) {
 func TestLoad_UsesDefaultsForInvalidOrEmptyValues(t *testing.T) {
 	t.Setenv("AUTH_TOKEN", "")
 	t.Setenv("CLIENT_ID", "")
+	t.Setenv("SC_API_BASE_URL", "")
+	t.Setenv("SC_USER_AGENT", "")
+	t.Setenv("SC_WEB_URL", "")
+	t.Setenv("CLIENT_ID_DISCOVERY", "invalid")
+	t.Setenv("OAUTH_CLIENT_ID", "")
+	t.Setenv("OAUTH_CLIENT_SECRET", "")
+	t.Setenv("OAUTH_REFRESH_TOKEN", "")
+	t.Setenv("OAUTH_TOKEN_URL", "")
+	t.Setenv("TOKEN_FILE", "")
+	t.Setenv("AUTH_TOKENS", "")
+	t.Setenv("CLIENT_IDS", "")
+	t.Setenv("CREDENTIAL_COOLDOWN", "invalid")
+	t.Setenv("RETRY_MAX_ATTEMPTS", "invalid")
+	t.Setenv("RETRY_BASE_DELAY", "invalid")
+	t.Setenv("RETRY_MAX_DELAY", "invalid")
+	t.Setenv("BREAKER_WINDOW", "invalid")
+	t.Setenv("BREAKER_MIN_REQUESTS", "invalid")
+	t.Setenv("BREAKER_FAILURE_RATIO", "invalid")
+	t.Setenv("BREAKER_SLOW_CALL", "invalid")
+	t.Setenv("BREAKER_OPEN_TIMEOUT", "invalid")
+	t.Setenv("BATCH_MAX_ITEMS", "invalid")
+	t.Setenv("BATCH_CONCURRENCY", "invalid")
+	t.Setenv("BATCH_ITEM_TIMEOUT", "invalid")
 	t.Setenv("RATE_LIMIT_REQUESTS", "invalid")
 	t.Setenv("RATE_LIMIT_WINDOW", "invalid")
 	t.Setenv("REQUEST_TIMEOUT", "invalid")
@@ -124,6 +232,52 @@ func TestLoad_UsesDefaultsForInvalidOrEmptyValues(t *testing.T) {
 		t.Fatalf("ClientID = %q, want empty string", cfg.ClientID)
 	}
 
+	if cfg.APIBaseURL != "https://api-v2.soundcloud.com" {
+		t.Fatalf("APIBaseURL = %q, want %q", cfg.APIBaseURL, "https://api-v2.soundcloud.com")
+	}
+
+	if cfg.UserAgent != "Go-http-client/1.1" {
+		t.Fatalf("UserAgent = %q, want %q", cfg.UserAgent, "Go-http-client/1.1")
+	}
+
+	if cfg.WebURL != "https://soundcloud.com" {
+		t.Fatalf("WebURL = %q, want %q", cfg.WebURL, "https://soundcloud.com")
+	}
+
+	if cfg.ClientIDDiscovery {
+		t.Fatal("ClientIDDiscovery = true, want false")
+	}
+
+	if cfg.OAuthClientID != "" || cfg.OAuthClientSecret != "" || cfg.OAuthRefreshToken != "" || cfg.TokenFile != "" {
+		t.Fatalf("OAuth settings = %q/%q/%q/%q, want empty", cfg.OAuthClientID, cfg.OAuthClientSecret, cfg.OAuthRefreshToken, cfg.TokenFile)
+	}
+
+	if cfg.AuthTokens != nil || cfg.ClientIDs != nil {
+		t.Fatalf("AuthTokens/ClientIDs = %q/%q, want nil", cfg.AuthTokens, cfg.ClientIDs)
+	}
+
+	if cfg.CredentialCooldown != 60*time.Second {
+		t.Fatalf("CredentialCooldown = %s, want %s", cfg.CredentialCooldown, 60*time.Second)
+	}
+
+	if cfg.RetryMaxAttempts != 3 || cfg.RetryBaseDelay != 250*time.Millisecond || cfg.RetryMaxDelay != 5*time.Second {
+		t.Fatalf("retry settings = %d/%s/%s, want 3/250ms/5s", cfg.RetryMaxAttempts, cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+
+	if cfg.BreakerWindow != 30*time.Second || cfg.BreakerMinRequests != 10 || cfg.BreakerFailureRatio != 0.5 ||
+		cfg.BreakerSlowCall != 10*time.Second || cfg.BreakerOpenTimeout != 30*time.Second {
+		t.Fatalf("breaker settings = %s/%d/%v/%s/%s, want 30s/10/0.5/10s/30s",
+			cfg.BreakerWindow, cfg.BreakerMinRequests, cfg.BreakerFailureRatio, cfg.BreakerSlowCall, cfg.BreakerOpenTimeout)
+	}
+
+	if cfg.BatchMaxItems != 50 || cfg.BatchConcurrency != 4 || cfg.BatchItemTimeout != 10*time.Second {
+		t.Fatalf("batch settings = %d/%d/%s, want 50/4/10s", cfg.BatchMaxItems, cfg.BatchConcurrency, cfg.BatchItemTimeout)
+	}
+
+	if cfg.OAuthTokenURL != "https://secure.soundcloud.com/oauth/token" {
+		t.Fatalf("OAuthTokenURL = %q, want %q", cfg.OAuthTokenURL, "https://secure.soundcloud.com/oauth/token")
+	}
+
 	if cfg.RateLimitRequests != 100 {
 		t.Fatalf("RateLimitRequests = %d, want %d", cfg.RateLimitRequests, 100)
 	}
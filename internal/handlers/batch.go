@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+// BatchStreamHandler resolves the stream URLs of many tracks in one request.
+// It counts against the rate limit once per track.
+func (h *Handlers) BatchStreamHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeBatchRequest(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := h.parseStreamOptions(w, req.Format, req.Protocol, req.Quality)
+	if !ok {
+		return
+	}
+
+	h.logInfo("Processing batch of %d tracks from %s", len(req.Tracks), utils.GetClientID(r))
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	resp := &types.BatchStreamResponse{
+		Results: make([]types.BatchStreamResult, len(req.Tracks)),
+		Total:   len(req.Tracks),
+	}
+	for i, result := range h.batchStreams(ctx, req.Tracks, opts) {
+		resp.Results[i] = result
+		if result.Error == nil {
+			resp.Succeeded++
+		}
+	}
+	resp.Failed = resp.Total - resp.Succeeded
+
+	h.logInfo("Batch done: %d succeeded, %d failed", resp.Succeeded, resp.Failed)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// BatchStreamEventsHandler works like BatchStreamHandler but writes every
+// result as soon as it is ready, as NDJSON or, if the client accepts
+// text/event-stream, as Server-Sent Events. A summary record follows the last
+// result unless the client went away first.
+func (h *Handlers) BatchStreamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeBatchRequest(w, r)
+	if !ok {
+		return
+	}
+	opts, ok := h.parseStreamOptions(w, req.Format, req.Protocol, req.Quality)
+	if !ok {
+		return
+	}
+
+	sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	h.logInfo("Streaming batch of %d tracks to %s (sse=%t)", len(req.Tracks), utils.GetClientID(r), sse)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	stream := utils.NewJSONStream(w, http.StatusOK, sse, h.Cfg.RequestTimeout)
+	summary := types.BatchStreamSummary{Done: true, Total: len(req.Tracks)}
+	for _, result := range h.batchStreams(ctx, req.Tracks, opts) {
+		if r.Context().Err() != nil {
+			break
+		}
+		if err := stream.Write("result", result); err != nil {
+			h.logDebug("Batch stream write failed: %v", err)
+			return
+		}
+		if result.Error == nil {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	if r.Context().Err() != nil {
+		h.logInfo("Client left batch stream after %d of %d tracks", summary.Succeeded+summary.Failed, summary.Total)
+		return
+	}
+
+	if err := stream.Write("summary", summary); err != nil {
+		h.logDebug("Batch stream write failed: %v", err)
+		return
+	}
+	h.logInfo("Batch stream done: %d succeeded, %d failed", summary.Succeeded, summary.Failed)
+}
+
+// decodeBatchRequest reads a batch request, checks its size and charges the
+// rate limiter for its items beyond the first, which the rate limit middleware
+// already counted. On failure it writes the error response and
+// returns false.
+func (h *Handlers) decodeBatchRequest(w http.ResponseWriter, r *http.Request) (*types.BatchStreamRequest, bool) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		h.logDebug("Invalid content type: %s", r.Header.Get("Content-Type"))
+		writeError(w, http.StatusBadRequest, "INVALID_CONTENT_TYPE", "Content-Type must be application/json")
+		return nil, false
+	}
+
+	var req types.BatchStreamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logDebug("JSON decode error: %v", err)
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON body")
+		return nil, false
+	}
+	if len(req.Tracks) == 0 {
+		writeError(w, http.StatusBadRequest, "EMPTY_BATCH", "'tracks' must not be empty")
+		return nil, false
+	}
+	if len(req.Tracks) > h.Cfg.BatchMaxItems {
+		h.logDebug("Batch of %d tracks exceeds limit", len(req.Tracks))
+		writeError(w, http.StatusBadRequest, "BATCH_TOO_LARGE", "At most "+strconv.Itoa(h.Cfg.BatchMaxItems)+" tracks per batch")
+		return nil, false
+	}
+
+	if extra := len(req.Tracks) - 1; extra > 0 {
+		if limited, details := h.RateLimiter.IsRateLimitedN(utils.GetClientID(r), extra); limited {
+			utils.WriteJSON(w, http.StatusTooManyRequests, details)
+			return nil, false
+		}
+	}
+	return &req, true
+}
+
+// batchStreams fetches stream URLs for items with a pool of
+// BatchConcurrency workers and yields the results as they complete, keyed by
+// the index of their item. Every item yields a result, failures included,
+// unless the caller stops early.
+func (h *Handlers) batchStreams(ctx context.Context, items []types.StreamRequest, opts scclient.StreamOptions) iter.Seq2[int, types.BatchStreamResult] {
+	return func(yield func(int, types.BatchStreamResult) bool) {
+		stop := make(chan struct{})
+		defer close(stop)
+
+		jobs := make(chan int)
+		done := make(chan types.BatchStreamResult)
+		var wg sync.WaitGroup
+		for range min(max(h.Cfg.BatchConcurrency, 1), len(items)) {
+			wg.Go(func() {
+				for i := range jobs {
+					select {
+					case done <- h.batchItem(ctx, i, items[i], opts):
+					case <-stop:
+						return
+					}
+				}
+			})
+		}
+		go func() {
+			defer close(jobs)
+			for i := range items {
+				select {
+				case jobs <- i:
+				case <-stop:
+					return
+				}
+			}
+		}()
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		for result := range done {
+			if !yield(result.Index, result) {
+				return
+			}
+		}
+	}
+}
+
+// batchItem fetches the stream URL of a single batch item within
+// BatchItemTimeout, reporting failures inside the result.
+func (h *Handlers) batchItem(ctx context.Context, index int, item types.StreamRequest, opts scclient.StreamOptions) types.BatchStreamResult {
+	ref := trackRef{URL: item.TrackURL, URN: item.URN}
+	if item.ID != 0 {
+		ref.ID = strconv.FormatInt(item.ID, 10)
+	}
+	result := types.BatchStreamResult{Index: index, Input: ref.String()}
+
+	if item.Format != "" || item.Protocol != "" || item.Quality != "" {
+		itemOpts, err := scclient.ParseStreamOptions(item.Format, item.Protocol, item.Quality)
+		if err != nil {
+			_, result.StreamResponse = h.streamErrorResponse(err)
+			return result
+		}
+		opts = itemOpts
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.Cfg.BatchItemTimeout)
+	defer cancel()
+
+	trackID, trackURL, err := h.streamTarget(ctx, ref)
+	if err == nil {
+		var stream *scclient.StreamResult
+		if stream, err = h.streamURL(ctx, trackID, trackURL, opts); err == nil {
+			result.StreamResponse = newStreamResponse(stream)
+			result.TrackInfo = newTrackInfo(stream.Track)
+			return result
+		}
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.StreamResponse = &types.StreamResponse{
+			Error:     "Timed out resolving track",
+			ErrorCode: "TIMEOUT",
+		}
+		return result
+	}
+	_, result.StreamResponse = h.streamErrorResponse(err)
+	return result
+}
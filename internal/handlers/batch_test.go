@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"soundcloud-api/internal/config"
+	"soundcloud-api/internal/middleware"
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/pkg/types"
+)
+
+// slowTrackID is held back by the stub upstream until its release channel is
+// closed or the request is cancelled.
+const slowTrackID = 2
+
+// newStubUpstream serves tracks 1 to 9 and their streams. Any
+// other ID is answered with 404. onSlow, if set, is called when the request
+// for slowTrackID arrives.
+func newStubUpstream(t *testing.T, release <-chan struct{}, onSlow func()) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if id < 1 || id > 9 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if id == slowTrackID {
+			if onSlow != nil {
+				onSlow()
+			}
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    id,
+			"kind":  "track",
+			"title": "Track " + strconv.FormatInt(id, 10),
+			"media": map[string]interface{}{
+				"transcodings": []interface{}{
+					map[string]interface{}{
+						"url":    "https://api-v2.soundcloud.com/media/" + strconv.FormatInt(id, 10) + "/stream/progressive",
+						"format": map[string]interface{}{"protocol": "progressive", "mime_type": "audio/mpeg"},
+					},
+					map[string]interface{}{
+						"url":    "https://api-v2.soundcloud.com/media/" + strconv.FormatInt(id, 10) + "/stream/hls",
+						"format": map[string]interface{}{"protocol": "hls", "mime_type": "audio/mpeg"},
+					},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/media/{id}/stream/{protocol}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"url": "https://cf-media.sndcdn.com/" + r.PathValue("id") + "." + r.PathValue("protocol"),
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newBatchTestHandlers returns handlers backed by upstream, allowing
+// rateLimit requests per hour.
+func newBatchTestHandlers(t *testing.T, upstream *httptest.Server, rateLimit int) *Handlers {
+	t.Helper()
+	cfg := &config.Config{
+		RequestTimeout:   5 * time.Second,
+		BatchItemTimeout: 5 * time.Second,
+		BatchConcurrency: 4,
+		BatchMaxItems:    10,
+		MaxTrackURLLen:   500,
+	}
+	rl := middleware.NewRateLimiter(rateLimit, time.Hour)
+	t.Cleanup(rl.Stop)
+	sc := scclient.New("", "cid", 5*time.Second,
+		scclient.WithBaseURL(upstream.URL),
+		scclient.WithHTTPClient(&http.Client{Transport: &http.Transport{DisableKeepAlives: true}}),
+		scclient.WithRetryPolicy(scclient.RetryPolicy{MaxAttempts: 1}),
+	)
+	return New(cfg, sc, rl, log.New(io.Discard, "", 0))
+}
+
+func newBatchRequest(ctx context.Context, target, body string) *http.Request {
+	r := httptest.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+// streamRecord is a result or summary record of a streamed batch.
+type streamRecord struct {
+	Index     int         `json:"index"`
+	ErrorCode interface{} `json:"error_code"`
+	Done      bool        `json:"done"`
+	Total     int         `json:"total"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+}
+
+func TestBatchStreamEventsHandler_WritesNDJSON(t *testing.T) {
+	h := newBatchTestHandlers(t, newStubUpstream(t, nil, nil), 100)
+
+	w := httptest.NewRecorder()
+	h.BatchStreamEventsHandler(w, newBatchRequest(context.Background(), "/soundcloud/stream-url/batch/stream",
+		`{"tracks":[{"id":1},{"id":404}]}`))
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 2 results and a summary:\n%s", len(lines), w.Body.String())
+	}
+	codes := map[int]interface{}{}
+	for _, line := range lines[:2] {
+		var rec streamRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil || rec.Done {
+			t.Fatalf("result line %q: %v", line, err)
+		}
+		codes[rec.Index] = rec.ErrorCode
+	}
+	if codes[0] != nil || codes[1] != "TRACK_NOT_FOUND" {
+		t.Fatalf("error codes by index = %v, want none for 0 and TRACK_NOT_FOUND for 1", codes)
+	}
+
+	var summary streamRecord
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatalf("summary line %q: %v", lines[2], err)
+	}
+	if !summary.Done || summary.Total != 2 || summary.Succeeded != 1 || summary.Failed != 1 {
+		t.Fatalf("summary = %+v, want done with 1 succeeded and 1 failed", summary)
+	}
+}
+
+func TestBatchStreamEventsHandler_WritesServerSentEvents(t *testing.T) {
+	h := newBatchTestHandlers(t, newStubUpstream(t, nil, nil), 100)
+
+	r := newBatchRequest(context.Background(), "/soundcloud/stream-url/batch/stream", `{"tracks":[{"id":1},{"id":3}]}`)
+	r.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	h.BatchStreamEventsHandler(w, r)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	body := w.Body.String()
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Fatalf("stream doesn't end with a blank line:\n%s", body)
+	}
+	events := strings.Split(strings.TrimSuffix(body, "\n\n"), "\n\n")
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 2 results and a summary:\n%s", len(events), body)
+	}
+	for i, event := range events {
+		name, data, ok := strings.Cut(event, "\n")
+		wantName := "event: result"
+		if i == len(events)-1 {
+			wantName = "event: summary"
+		}
+		if !ok || name != wantName || !strings.HasPrefix(data, "data: ") {
+			t.Fatalf("event %d = %q, want %q with a data line", i, event, wantName)
+		}
+		var rec streamRecord
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &rec); err != nil {
+			t.Fatalf("event %d data: %v", i, err)
+		}
+		if i == len(events)-1 && (!rec.Done || rec.Succeeded != 2) {
+			t.Fatalf("summary = %+v, want done with 2 succeeded", rec)
+		}
+	}
+}
+
+func TestBatchStreamEventsHandler_FlushesEachResult(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	h := newBatchTestHandlers(t, newStubUpstream(t, release, nil), 100)
+	srv := httptest.NewServer(http.HandlerFunc(h.BatchStreamEventsHandler))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"tracks":[{"id":1},{"id":2}]}`))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Track 2 is held back, so the first result must arrive on its own.
+	lines := bufio.NewReader(resp.Body)
+	first, err := lines.ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading first result: %v", err)
+	}
+	var rec streamRecord
+	if err := json.Unmarshal([]byte(first), &rec); err != nil || rec.Index != 0 || rec.Done {
+		t.Fatalf("first record = %q (%v), want the result of track 1", first, err)
+	}
+}
+
+func TestBatchStreamEventsHandler_StopsWhenClientLeaves(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	upstream := newStubUpstream(t, nil, cancel)
+	h := newBatchTestHandlers(t, upstream, 100)
+	h.Cfg.BatchConcurrency = 1
+
+	before := runtime.NumGoroutine()
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.BatchStreamEventsHandler(w, newBatchRequest(ctx, "/soundcloud/stream-url/batch/stream",
+			`{"tracks":[{"id":1},{"id":2},{"id":3},{"id":4}]}`))
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("handler kept running after the client left")
+	}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(w.Body.String()), "\n") {
+		var rec streamRecord
+		if line != "" && json.Unmarshal([]byte(line), &rec) == nil && rec.Done {
+			t.Fatalf("summary written after the client left:\n%s", w.Body.String())
+		}
+	}
+	if strings.Count(w.Body.String(), "\n") > 1 {
+		t.Fatalf("results written after the client left:\n%s", w.Body.String())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Fatalf("%d goroutines left running, had %d before", n, before)
+	}
+}
+
+func TestBatchStreamHandler_ReportsEachItemInOrder(t *testing.T) {
+	h := newBatchTestHandlers(t, newStubUpstream(t, nil, nil), 100)
+	h.Cfg.BatchItemTimeout = 100 * time.Millisecond
+
+	w := httptest.NewRecorder()
+	h.BatchStreamHandler(w, newBatchRequest(context.Background(), "/soundcloud/stream-url/batch",
+		`{"tracks":[{"id":2},{"id":404},{"id":1,"protocol":"hls"}],"protocol":"progressive"}`))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp types.BatchStreamResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Total != 3 || resp.Succeeded != 1 || resp.Failed != 2 || len(resp.Results) != 3 {
+		t.Fatalf("response = %+v, want 3 results with 1 succeeded and 2 failed", resp)
+	}
+	for i, result := range resp.Results {
+		if result.Index != i {
+			t.Fatalf("results[%d].Index = %d, want results in input order", i, result.Index)
+		}
+	}
+	if code := resp.Results[0].ErrorCode; code != "TIMEOUT" {
+		t.Fatalf("slow item error_code = %v, want TIMEOUT", code)
+	}
+	if code := resp.Results[1].ErrorCode; code != "TRACK_NOT_FOUND" {
+		t.Fatalf("missing item error_code = %v, want TRACK_NOT_FOUND", code)
+	}
+	if good := resp.Results[2]; good.Error != nil || good.Protocol != "hls" {
+		t.Fatalf("good item = %+v, want an HLS stream from its own override", good.StreamResponse)
+	}
+}
+
+func TestBatchStreamHandler_ChargesRateLimitPerItem(t *testing.T) {
+	h := newBatchTestHandlers(t, newStubUpstream(t, nil, nil), 5)
+	// Routed like in cmd/api: the middleware charges the first item.
+	handler := middleware.RateLimitMiddleware(h.RateLimiter, h.BatchStreamHandler)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, newBatchRequest(context.Background(), "/soundcloud/stream-url/batch", body))
+		return w
+	}
+
+	if w := post(`{"tracks":[{"id":1},{"id":3},{"id":4}]}`); w.Code != http.StatusOK {
+		t.Fatalf("first batch status = %d, want 200", w.Code)
+	}
+
+	w := post(`{"tracks":[{"id":1},{"id":3},{"id":4}]}`)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("batch beyond the allowance: status = %d, want 429", w.Code)
+	}
+	var limited types.RateLimitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &limited); err != nil {
+		t.Fatalf("decoding 429 response: %v", err)
+	}
+	if limited.Details["remaining"] != float64(1) {
+		t.Fatalf("details = %v, want 1 remaining", limited.Details)
+	}
+
+	// Only the first item of the rejected batch was charged.
+	if w := post(`{"tracks":[{"id":1}]}`); w.Code != http.StatusOK {
+		t.Fatalf("single track within the allowance: status = %d, want 200", w.Code)
+	}
+	if w := post(`{"tracks":[{"id":1}]}`); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request past the limit: status = %d, want 429", w.Code)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"soundcloud-api/internal/captions"
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+const (
+	// commentCueDuration is how long a timed comment stays on screen.
+	commentCueDuration = 4 * time.Second
+	// maxTimelineComments caps how many comments are fetched for a subtitle
+	// export, since those can't be paginated.
+	maxTimelineComments = 5000
+)
+
+func (h *Handlers) CommentsHandler(w http.ResponseWriter, r *http.Request) {
+	trackURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "vtt", "srt":
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Invalid 'format' parameter (allowed: json, vtt, srt)")
+		return
+	}
+	page, ok := h.pageOptions(w, r)
+	if !ok {
+		return
+	}
+
+	h.logRequest(r, trackURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	track, err := h.ScClient.ResolveTrack(ctx, trackURL)
+	if err != nil {
+		h.writeClientError(w, err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+		return
+	}
+
+	if format == "vtt" || format == "srt" {
+		h.writeCommentTimeline(ctx, w, track, format)
+		return
+	}
+
+	comments, err := h.ScClient.GetTrackComments(ctx, track.ID, page)
+	if err != nil {
+		h.writeClientError(w, err, "UPSTREAM_ERROR", "Failed to load comments")
+		return
+	}
+
+	resp := &types.CommentsResponse{
+		TrackInfo:  newTrackInfo(track),
+		Comments:   make([]types.CommentInfo, 0, len(comments.Collection)),
+		NextCursor: comments.NextCursor(),
+	}
+	for _, c := range comments.Collection {
+		info := types.CommentInfo{
+			ID:        c.ID,
+			Body:      c.Body,
+			Timestamp: c.Timestamp,
+			CreatedAt: c.CreatedAt,
+			UserID:    c.UserID,
+		}
+		if c.User != nil {
+			info.Username = c.User.Username
+		}
+		resp.Comments = append(resp.Comments, info)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// writeCommentTimeline fetches all timed comments of a track and writes them
+// as a WebVTT or SRT subtitle track.
+func (h *Handlers) writeCommentTimeline(ctx context.Context, w http.ResponseWriter, track *scclient.Track, format string) {
+	var cues []captions.Cue
+	for c, err := range h.ScClient.TrackComments(ctx, track.ID, scclient.IterOptions{PageSize: 200, MaxItems: maxTimelineComments}) {
+		if err != nil {
+			h.writeClientError(w, err, "UPSTREAM_ERROR", "Failed to load comments")
+			return
+		}
+		if c.Timestamp == nil {
+			continue
+		}
+		cues = append(cues, commentCue(&c, track.Duration))
+	}
+	slices.SortStableFunc(cues, func(a, b captions.Cue) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+
+	var buf bytes.Buffer
+	contentType := "text/vtt; charset=utf-8"
+	if format == "srt" {
+		contentType = "application/x-subrip; charset=utf-8"
+		captions.WriteSRT(&buf, cues)
+	} else {
+		captions.WriteVTT(&buf, cues)
+	}
+
+	h.logDebug("Track %d: exported %d timed comments as %s", track.ID, len(cues), format)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func commentCue(c *scclient.Comment, trackDuration int64) captions.Cue {
+	start := time.Duration(*c.Timestamp) * time.Millisecond
+	end := start + commentCueDuration
+	if limit := time.Duration(trackDuration) * time.Millisecond; limit > start && end > limit {
+		end = limit
+	}
+
+	text := c.Body
+	if c.User != nil && c.User.Username != "" {
+		text = c.User.Username + ": " + text
+	}
+	return captions.Cue{Start: start, End: end, Text: text}
+}
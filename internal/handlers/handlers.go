@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,8 +26,7 @@ type Handlers struct {
 	Logger      *log.Logger
 }
 
-func New(cfg *config.Config, scClient *scclient.SoundCloudClient, rateLimiter *middleware.RateLimiter) *Handlers {
-	logger := initLogger(cfg.LogFile)
+func New(cfg *config.Config, scClient *scclient.SoundCloudClient, rateLimiter *middleware.RateLimiter, logger *log.Logger) *Handlers {
 	return &Handlers{
 		Cfg:         cfg,
 		ScClient:    scClient,
@@ -34,7 +35,8 @@ func New(cfg *config.Config, scClient *scclient.SoundCloudClient, rateLimiter *m
 	}
 }
 
-func initLogger(logFile string) *log.Logger {
+// NewLogger returns a logger that writes to both stdout and logFile.
+func NewLogger(logFile string) *log.Logger {
 	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		log.Fatalf("can't open log file: %v", err)
@@ -67,22 +69,19 @@ func (h *Handlers) logRequest(r *http.Request, trackURL string) {
 	}
 }
 
-func (h *Handlers) logResponse(result map[string]interface{}) {
+func (h *Handlers) logResponse(result *types.StreamResponse) {
 	if h.Cfg.Debug {
-		redacted := utils.DeepCopyMap(result)
-		if _, ok := redacted["stream_url"]; ok {
-			redacted["stream_url"] = "[REDACTED]"
+		redacted := *result
+		if redacted.StreamURL != nil {
+			redacted.StreamURL = "[REDACTED]"
 		}
 		b, _ := json.MarshalIndent(redacted, "", "  ")
 		h.logDebug("Response: %s", string(b))
 	} else {
-		streamURL, hasStream := result["stream_url"]
-		errorMsg, hasError := result["error"]
-
-		if hasStream && streamURL != nil && (!hasError || errorMsg == nil) {
+		if result.StreamURL != nil && result.Error == nil {
 			h.logInfo("Success: stream URL obtained")
 		} else {
-			errorCode, _ := result["error_code"].(string)
+			errorCode, _ := result.ErrorCode.(string)
 			h.logInfo("Failed: %s", errorCode)
 		}
 	}
@@ -94,30 +93,71 @@ func (h *Handlers) HealthHandler(w http.ResponseWriter, r *http.Request) {
 
 	h.logDebug("Health check request from %s", utils.GetClientID(r))
 
-	valid, errMsg := h.ScClient.ValidateToken(ctx)
-	status := "healthy"
-	statusCode := http.StatusOK
-	if !valid {
-		status = "degraded"
-		statusCode = http.StatusServiceUnavailable
-		h.logError("Token validation failed: %s", errMsg)
-	} else {
-		h.logDebug("Token validation successful")
-	}
-
 	health := &types.HealthResponse{
-		Status:    status,
 		Service:   "soundcloud-api",
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Version:   "1.0.0",
 	}
 
-	if !valid {
-		health.TokenError = errMsg
+	var validCount int
+	for _, cs := range h.ScClient.CheckCredentials(ctx) {
+		ch := types.CredentialHealth{
+			Index:      cs.Index,
+			ClientID:   maskSecret(cs.ClientID),
+			Status:     "ok",
+			LastStatus: cs.LastStatus,
+			Requests:   cs.Requests,
+		}
+		switch {
+		case !cs.Valid:
+			ch.Status = "invalid"
+			ch.Error = cs.Error
+			h.logError("Credential %d validation failed: %s", cs.Index, cs.Error)
+			if cs.Index == 0 {
+				health.TokenError = cs.Error
+			}
+		case cs.CoolingDown:
+			ch.Status = "cooldown"
+			ch.CooldownUntil = cs.CooldownUntil.UTC().Format(time.RFC3339)
+		}
+		if cs.Valid {
+			validCount++
+		}
+		health.Credentials = append(health.Credentials, ch)
+	}
+
+	breaker := h.ScClient.BreakerStatus()
+	health.Upstream = &types.UpstreamHealth{
+		Breaker:  breaker.State.String(),
+		Requests: breaker.Requests,
+		Failures: breaker.Failures,
+	}
+	if breaker.State == scclient.BreakerOpen {
+		health.Upstream.OpenUntil = breaker.OpenUntil.UTC().Format(time.RFC3339)
+	}
+
+	// One bad credential out of several only degrades the service; it's
+	// unavailable once none is left or the breaker is open.
+	health.Status = "healthy"
+	statusCode := http.StatusOK
+	if validCount < len(health.Credentials) || breaker.State != scclient.BreakerClosed {
+		health.Status = "degraded"
+	}
+	if validCount == 0 || breaker.State == scclient.BreakerOpen {
+		statusCode = http.StatusServiceUnavailable
 	}
+	h.logDebug("%d of %d credentials valid", validCount, len(health.Credentials))
 
 	utils.WriteJSON(w, statusCode, health)
-	h.logDebug("Health check response: %s", status)
+	h.logDebug("Health check response: %s", health.Status)
+}
+
+// maskSecret keeps the first and last four characters of s.
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", len(s)-8) + s[len(s)-4:]
 }
 
 func (h *Handlers) PostStreamHandler(w http.ResponseWriter, r *http.Request) {
@@ -141,58 +181,315 @@ func (h *Handlers) PostStreamHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.processStreamRequest(w, r, sr.TrackURL)
+	opts, ok := h.parseStreamOptions(w, sr.Format, sr.Protocol, sr.Quality)
+	if !ok {
+		return
+	}
+
+	ref := trackRef{URL: sr.TrackURL, URN: sr.URN}
+	if sr.ID != 0 {
+		ref.ID = strconv.FormatInt(sr.ID, 10)
+	}
+	h.processStreamRequest(w, r, ref, opts)
 }
 
 func (h *Handlers) GetStreamHandler(w http.ResponseWriter, r *http.Request) {
-	trackURL := strings.TrimSpace(r.URL.Query().Get("url"))
-	if trackURL == "" {
+	q := r.URL.Query()
+	ref := trackRef{
+		URL: strings.TrimSpace(q.Get("url")),
+		ID:  strings.TrimSpace(q.Get("id")),
+		URN: strings.TrimSpace(q.Get("urn")),
+	}
+	if ref.URL == "" && ref.ID == "" && ref.URN == "" {
 		h.logDebug("Missing URL parameter")
 		utils.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
-			"error":      "Missing 'url' parameter",
+			"error":      "Missing 'url', 'id' or 'urn' parameter",
 			"error_code": "MISSING_URL_PARAM",
 		})
 		return
 	}
 
-	h.processStreamRequest(w, r, trackURL)
+	opts, ok := h.parseStreamOptions(w, q.Get("format"), q.Get("protocol"), q.Get("quality"))
+	if !ok {
+		return
+	}
+
+	h.processStreamRequest(w, r, ref, opts)
 }
 
-func (h *Handlers) processStreamRequest(w http.ResponseWriter, r *http.Request, trackURL string) {
-	trackURL = strings.TrimSpace(trackURL)
+func (h *Handlers) parseStreamOptions(w http.ResponseWriter, format, protocol, quality string) (scclient.StreamOptions, bool) {
+	opts, err := scclient.ParseStreamOptions(format, protocol, quality)
+	if err != nil {
+		var scErr *scclient.Error
+		errors.As(err, &scErr)
+		h.logDebug("Invalid stream options: %v", err)
+		utils.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":      scErr.Message,
+			"error_code": scErr.Code,
+		})
+		return opts, false
+	}
+	return opts, true
+}
+
+// trackRef is how a request names a track: by URL, by ID or by URN.
+type trackRef struct {
+	URL string
+	ID  string
+	URN string
+}
+
+func (ref trackRef) String() string {
+	switch {
+	case ref.ID != "":
+		return "id " + ref.ID
+	case ref.URN != "":
+		return ref.URN
+	}
+	return ref.URL
+}
+
+// streamTarget checks ref and returns the track ID it names, or 0 and the
+// normalized URL for a soundcloud.com URL. API URLs such as
+// https://api.soundcloud.com/tracks/123 count as IDs. Errors are
+// *scclient.Error values describing what's wrong with the request.
+func (h *Handlers) streamTarget(ctx context.Context, ref trackRef) (int64, string, error) {
+	set := 0
+	for _, v := range []string{ref.URL, ref.ID, ref.URN} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		h.logDebug("More than one of url, id and urn given")
+		return 0, "", &scclient.Error{Code: "INVALID_PARAM", Message: "Use only one of 'url', 'id' and 'urn'"}
+	}
+
+	if raw := ref.ID + ref.URN; raw != "" {
+		id, err := scclient.ParseTrackID(raw)
+		if err != nil {
+			h.logDebug("Invalid track ID: %q", raw)
+			return 0, "", err
+		}
+		return id, "", nil
+	}
+
+	trackURL, err := h.normalizeURL(ctx, ref.URL)
+	if err != nil {
+		return 0, "", err
+	}
+	if id, ok := scclient.TrackIDFromURL(trackURL); ok {
+		return id, "", nil
+	}
 	isValid, errMsg := utils.ValidateSoundCloudURL(trackURL, h.Cfg.MaxTrackURLLen)
 	if !isValid {
 		h.logDebug("URL validation failed: %s", errMsg)
-		utils.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
-			"error":      errMsg,
-			"error_code": "INVALID_URL",
-		})
-		return
+		return 0, "", &scclient.Error{Code: "INVALID_URL", Message: errMsg}
 	}
+	return 0, trackURL, nil
+}
 
-	h.logRequest(r, trackURL)
+// streamURL fetches the stream URL of a target returned by streamTarget.
+func (h *Handlers) streamURL(ctx context.Context, trackID int64, trackURL string, opts scclient.StreamOptions) (*scclient.StreamResult, error) {
+	if trackID != 0 {
+		return h.ScClient.GetStreamURLByID(ctx, trackID, opts)
+	}
+	return h.ScClient.GetStreamURL(ctx, trackURL, opts)
+}
 
+func (h *Handlers) processStreamRequest(w http.ResponseWriter, r *http.Request, ref trackRef, opts scclient.StreamOptions) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
 	defer cancel()
 
-	result, err := h.ScClient.GetStreamURL(ctx, trackURL)
+	trackID, trackURL, err := h.streamTarget(ctx, ref)
 	if err != nil {
-		h.logError("Unexpected error getting stream URL: %v", err)
-		utils.WriteJSON(w, http.StatusInternalServerError, map[string]interface{}{
-			"error":      "Internal server error",
-			"error_code": "INTERNAL_ERROR",
-		})
+		h.writeClientError(w, err, "INVALID_URL", "Invalid SoundCloud URL format")
 		return
 	}
 
-	h.logResponse(result)
+	h.logRequest(r, ref.String())
 
-	if result["stream_url"] != nil && result["error"] == nil {
-		utils.WriteJSON(w, http.StatusOK, result)
+	result, err := h.streamURL(ctx, trackID, trackURL, opts)
+	if err != nil {
+		status, resp := h.streamErrorResponse(err)
+		h.logResponse(resp)
+		utils.WriteJSON(w, status, resp)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusBadRequest, result)
+	resp := newStreamResponse(result)
+	resp.TrackInfo = newTrackInfo(result.Track)
+	resp.CacheInfo = &types.CacheInfo{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		TTLSeconds: 3600,
+	}
+	h.logResponse(resp)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func newStreamResponse(result *scclient.StreamResult) *types.StreamResponse {
+	return &types.StreamResponse{
+		StreamURL: result.URL,
+		Protocol:  result.Transcoding.Format.Protocol,
+		Format:    result.Transcoding.FormatName(),
+		Quality:   result.Transcoding.Quality,
+		MimeType:  result.Transcoding.Format.MimeType,
+		Private:   result.Track.IsPrivate(),
+	}
+}
+
+// streamErrorResponse turns an error from the client into a stream response
+// and the HTTP status to send it with.
+func (h *Handlers) streamErrorResponse(err error) (int, *types.StreamResponse) {
+	var scErr *scclient.Error
+	if !errors.As(err, &scErr) {
+		h.logError("Unexpected error getting stream URL: %v", err)
+		return http.StatusInternalServerError, &types.StreamResponse{
+			Error:     "Internal server error",
+			ErrorCode: "INTERNAL_ERROR",
+		}
+	}
+	return errorStatus(err), &types.StreamResponse{
+		Error:     scErr.Message,
+		ErrorCode: scErr.Code,
+	}
+}
+
+// errorStatus is the HTTP status an error from the client is reported with.
+// Errors that aren't classified are assumed to be caused by the request.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, scclient.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, scclient.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scclient.ErrGeoBlocked):
+		return http.StatusUnavailableForLegalReasons
+	case errors.Is(err, scclient.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, scclient.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, scclient.ErrNoTranscoding):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, scclient.ErrUpstream):
+		return http.StatusBadGateway
+	}
+	return http.StatusBadRequest
+}
+
+func newTrackInfo(t *scclient.Track) *types.TrackInfo {
+	var artist string
+	if t.User != nil {
+		artist = t.User.Username
+	}
+	return &types.TrackInfo{
+		ID:           t.ID,
+		Title:        utils.IfString(t.Title, "Unknown"),
+		Artist:       utils.IfString(artist, "Unknown"),
+		Duration:     t.Duration,
+		PermalinkURL: t.PermalinkURL,
+		ArtworkURL:   t.ArtworkURL,
+		Genre:        t.Genre,
+		ReleaseDate:  t.ReleaseDate,
+	}
+}
+
+// urlParam reads and validates the "url" query parameter. On failure it writes
+// the error response and returns false.
+func (h *Handlers) urlParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	rawURL := strings.TrimSpace(r.URL.Query().Get("url"))
+	if rawURL == "" {
+		h.logDebug("Missing URL parameter")
+		writeError(w, http.StatusBadRequest, "MISSING_URL_PARAM", "Missing 'url' parameter")
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+	rawURL, err := h.normalizeURL(ctx, rawURL)
+	if err != nil {
+		h.writeClientError(w, err, "INVALID_URL", "Invalid SoundCloud URL format")
+		return "", false
+	}
+
+	isValid, errMsg := utils.ValidateSoundCloudURL(rawURL, h.Cfg.MaxTrackURLLen)
+	if !isValid {
+		h.logDebug("URL validation failed: %s", errMsg)
+		writeError(w, http.StatusBadRequest, "INVALID_URL", errMsg)
+		return "", false
+	}
+	return rawURL, true
+}
+
+// normalizeURL expands short links and canonicalizes a pasted SoundCloud URL.
+func (h *Handlers) normalizeURL(ctx context.Context, rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		// Left to validation to report.
+		return rawURL, nil
+	}
+	if len(rawURL) > h.Cfg.MaxTrackURLLen {
+		h.logDebug("URL too long: %d characters", len(rawURL))
+		return "", &scclient.Error{Code: "INVALID_URL", Message: "URL too long (max " + strconv.Itoa(h.Cfg.MaxTrackURLLen) + " characters)"}
+	}
+	normalized, err := h.ScClient.NormalizeURL(ctx, rawURL)
+	if err != nil {
+		h.logDebug("URL normalization failed: %v", err)
+		return "", scclient.Classify(err, "INVALID_URL", "Invalid SoundCloud URL format")
+	}
+	if normalized != rawURL {
+		h.logDebug("Normalized URL %s to %s", rawURL, normalized)
+	}
+	return normalized, nil
+}
+
+// boolParam parses an optional boolean query parameter. On failure it writes
+// the error response and returns false as second value.
+func (h *Handlers) boolParam(w http.ResponseWriter, r *http.Request, name string) (bool, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, true
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		h.logDebug("Invalid %s parameter: %q", name, raw)
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Invalid '"+name+"' parameter")
+		return false, false
+	}
+	return v, true
+}
+
+// intParam parses an optional positive integer query parameter, returning def
+// when it's absent. On failure it writes the error response and returns false.
+func (h *Handlers) intParam(w http.ResponseWriter, r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v <= 0 {
+		h.logDebug("Invalid %s parameter: %q", name, raw)
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Invalid '"+name+"' parameter")
+		return 0, false
+	}
+	return v, true
+}
+
+// writeClientError writes an error returned by the SoundCloud client. Missing
+// resources are reported with the given fallback.
+func (h *Handlers) writeClientError(w http.ResponseWriter, err error, fallbackCode, fallbackMessage string) {
+	scErr := scclient.Classify(err, fallbackCode, fallbackMessage)
+	h.logInfo("Failed: %s (%v)", scErr.Code, err)
+	writeError(w, errorStatus(scErr), scErr.Code, scErr.Message)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	utils.WriteJSON(w, status, map[string]interface{}{
+		"error":      message,
+		"error_code": code,
+	})
 }
 
 func (h *Handlers) NotFoundHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+// streamConcurrency bounds how many stream URLs are fetched in parallel when
+// a list of tracks is requested with streams=true.
+const streamConcurrency = 4
+
+func (h *Handlers) PlaylistHandler(w http.ResponseWriter, r *http.Request) {
+	playlistURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	withStreams, ok := h.boolParam(w, r, "streams")
+	if !ok {
+		return
+	}
+	var opts scclient.StreamOptions
+	if withStreams {
+		if opts, ok = h.parseStreamOptions(w, q.Get("format"), q.Get("protocol"), q.Get("quality")); !ok {
+			return
+		}
+	}
+
+	h.logRequest(r, playlistURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	playlist, err := h.ScClient.ResolvePlaylist(ctx, playlistURL)
+	if err != nil {
+		h.writeClientError(w, err, "PLAYLIST_NOT_FOUND", "Playlist not found or unavailable")
+		return
+	}
+
+	resp := &types.PlaylistResponse{
+		PlaylistInfo: *newPlaylistInfo(playlist),
+		Description:  playlist.Description,
+		Tracks:       make([]types.PlaylistTrack, len(playlist.Tracks)),
+	}
+
+	available := make([]*scclient.Track, len(playlist.Tracks))
+	for i := range playlist.Tracks {
+		track := &playlist.Tracks[i]
+		item := &resp.Tracks[i]
+		item.Position = i + 1
+		item.ID = track.ID
+		item.Available = !scclient.IsStub(track)
+		if item.Available {
+			item.TrackInfo = newTrackInfo(track)
+			available[i] = track
+		}
+	}
+	if withStreams {
+		for i, stream := range h.trackStreams(ctx, available, opts) {
+			resp.Tracks[i].Stream = stream
+		}
+	}
+
+	h.logDebug("Playlist %d resolved with %d tracks", playlist.ID, len(resp.Tracks))
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func newPlaylistInfo(p *scclient.Playlist) *types.PlaylistInfo {
+	info := &types.PlaylistInfo{
+		ID:           p.ID,
+		Title:        p.Title,
+		PermalinkURL: p.PermalinkURL,
+		ArtworkURL:   p.ArtworkURL,
+		Duration:     p.Duration,
+		IsAlbum:      p.IsAlbum,
+		TrackCount:   p.TrackCount,
+	}
+	if p.User != nil {
+		info.Artist = p.User.Username
+	}
+	return info
+}
+
+// trackStreams fetches stream URLs for tracks concurrently. The result is in
+// the same order as tracks; nil tracks are skipped and get a nil response.
+func (h *Handlers) trackStreams(ctx context.Context, tracks []*scclient.Track, opts scclient.StreamOptions) []*types.StreamResponse {
+	streams := make([]*types.StreamResponse, len(tracks))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, streamConcurrency)
+	for i, track := range tracks {
+		if track == nil {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			streams[i] = h.trackStream(ctx, track, opts)
+		})
+	}
+	wg.Wait()
+	return streams
+}
+
+// trackStream fetches the stream URL for a resolved track, reporting failures
+// inside the returned response rather than as an error.
+func (h *Handlers) trackStream(ctx context.Context, track *scclient.Track, opts scclient.StreamOptions) *types.StreamResponse {
+	result, err := h.ScClient.StreamTrack(ctx, track, opts)
+	if err != nil {
+		_, resp := h.streamErrorResponse(err)
+		return resp
+	}
+	return newStreamResponse(result)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+const defaultRelatedLimit = 10
+
+func (h *Handlers) RelatedHandler(w http.ResponseWriter, r *http.Request) {
+	trackURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	source := q.Get("source")
+	switch source {
+	case "":
+		source = "related"
+	case "related", "station":
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Invalid 'source' parameter (allowed: related, station)")
+		return
+	}
+	limit, ok := h.intParam(w, r, "limit", defaultRelatedLimit)
+	if !ok {
+		return
+	}
+	withStreams, ok := h.boolParam(w, r, "streams")
+	if !ok {
+		return
+	}
+	var opts scclient.StreamOptions
+	if withStreams {
+		if opts, ok = h.parseStreamOptions(w, q.Get("format"), q.Get("protocol"), q.Get("quality")); !ok {
+			return
+		}
+	}
+	exclude := newExclusionSet(q.Get("exclude"))
+
+	h.logRequest(r, trackURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	seed, err := h.ScClient.ResolveTrack(ctx, trackURL)
+	if err != nil {
+		h.writeClientError(w, err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+		return
+	}
+	exclude.addTrack(seed)
+
+	// Ask for enough extra tracks to still fill the limit after filtering.
+	page := scclient.PageOptions{Limit: limit + exclude.size()}
+	var related *scclient.Page[scclient.Track]
+	if source == "station" && seed.StationURN == "" {
+		// Not every track has a station; fall back and say so in the
+		// response.
+		h.logDebug("Track %d has no station, using related tracks", seed.ID)
+		source = "related"
+	}
+	if source == "station" {
+		related, err = h.ScClient.GetStationTracks(ctx, seed.StationURN, page)
+	} else {
+		related, err = h.ScClient.GetRelatedTracks(ctx, seed.ID, page)
+	}
+	if err != nil {
+		h.writeClientError(w, err, "UPSTREAM_ERROR", "Failed to load related tracks")
+		return
+	}
+
+	var tracks []*scclient.Track
+	for i := range related.Collection {
+		t := &related.Collection[i]
+		if len(tracks) == limit {
+			break
+		}
+		if exclude.contains(t) {
+			continue
+		}
+		exclude.addTrack(t)
+		tracks = append(tracks, t)
+	}
+
+	resp := &types.RelatedResponse{
+		Source: source,
+		Seed:   newTrackInfo(seed),
+		Tracks: make([]types.RelatedTrack, len(tracks)),
+	}
+	for i, t := range tracks {
+		resp.Tracks[i].TrackInfo = *newTrackInfo(t)
+	}
+	if withStreams {
+		for i, stream := range h.trackStreams(ctx, tracks, opts) {
+			resp.Tracks[i].Stream = stream
+		}
+	}
+
+	h.logDebug("Track %d: returned %d %s tracks", seed.ID, len(resp.Tracks), source)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// exclusionSet matches tracks by ID or permalink URL.
+type exclusionSet struct {
+	ids   map[int64]bool
+	links map[string]bool
+}
+
+// newExclusionSet parses a comma-separated list of track IDs and URLs.
+func newExclusionSet(raw string) *exclusionSet {
+	e := &exclusionSet{ids: map[int64]bool{}, links: map[string]bool{}}
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			e.ids[id] = true
+		} else {
+			e.links[normalizeLink(v)] = true
+		}
+	}
+	return e
+}
+
+func (e *exclusionSet) addTrack(t *scclient.Track) {
+	e.ids[t.ID] = true
+}
+
+func (e *exclusionSet) contains(t *scclient.Track) bool {
+	return e.ids[t.ID] || (t.PermalinkURL != "" && e.links[normalizeLink(t.PermalinkURL)])
+}
+
+func (e *exclusionSet) size() int {
+	return len(e.ids) + len(e.links)
+}
+
+func normalizeLink(s string) string {
+	return strings.ToLower(strings.TrimRight(s, "/"))
+}
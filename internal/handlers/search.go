@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+func (h *Handlers) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		h.logDebug("Missing q parameter")
+		writeError(w, http.StatusBadRequest, "MISSING_QUERY_PARAM", "Missing 'q' parameter")
+		return
+	}
+	searchType := q.Get("type")
+	if searchType == "" {
+		searchType = "tracks"
+	}
+
+	page, ok := h.pageOptions(w, r)
+	if !ok {
+		return
+	}
+	if page.Offset, ok = h.intParam(w, r, "offset", 0); !ok {
+		return
+	}
+
+	h.logDebug("Search %s for %q from %s", searchType, query, utils.GetClientID(r))
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	result, err := h.ScClient.Search(ctx, query, searchType, page)
+	if err != nil {
+		h.writeClientError(w, err, "UPSTREAM_ERROR", "Search failed")
+		return
+	}
+
+	resp := &types.SearchResponse{
+		Query:        query,
+		Type:         searchType,
+		TotalResults: result.TotalResults,
+		NextCursor:   result.NextCursor,
+	}
+	for i := range result.Tracks {
+		resp.Tracks = append(resp.Tracks, *newTrackInfo(&result.Tracks[i]))
+	}
+	for i := range result.Users {
+		resp.Users = append(resp.Users, *newUserInfo(&result.Users[i]))
+	}
+	for i := range result.Playlists {
+		resp.Playlists = append(resp.Playlists, *newPlaylistInfo(&result.Playlists[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+)
+
+// trackFields holds the JSON names of the scclient.Track fields accepted by
+// the fields parameter of TrackHandler. track_authorization is never returned,
+// so it can't be selected either.
+var trackFields = func() map[string]bool {
+	fields := jsonFields(reflect.TypeFor[scclient.Track]())
+	delete(fields, "track_authorization")
+	return fields
+}()
+
+// TrackHandler returns a track's metadata as resolved from SoundCloud, without
+// fetching a stream URL. fields optionally limits the response to the given
+// comma-separated top-level fields.
+func (h *Handlers) TrackHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ref := trackRef{
+		URL: strings.TrimSpace(q.Get("url")),
+		ID:  strings.TrimSpace(q.Get("id")),
+		URN: strings.TrimSpace(q.Get("urn")),
+	}
+	if ref.URL == "" && ref.ID == "" && ref.URN == "" {
+		h.logDebug("Missing URL parameter")
+		writeError(w, http.StatusBadRequest, "MISSING_URL_PARAM", "Missing 'url', 'id' or 'urn' parameter")
+		return
+	}
+
+	fields, ok := h.fieldsParam(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	trackID, trackURL, err := h.streamTarget(ctx, ref)
+	if err != nil {
+		h.writeClientError(w, err, "INVALID_URL", "Invalid SoundCloud URL format")
+		return
+	}
+
+	h.logRequest(r, ref.String())
+
+	var track *scclient.Track
+	if trackID != 0 {
+		track, err = h.ScClient.GetTrackByID(ctx, trackID)
+	} else {
+		track, err = h.ScClient.ResolveTrack(ctx, trackURL)
+	}
+	if err != nil {
+		h.writeClientError(w, err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+		return
+	}
+	// The authorization is only good for fetching this track's streams and
+	// is of no use without the stream endpoints, which issue their own.
+	track.TrackAuthorization = ""
+
+	h.logDebug("Track %d resolved", track.ID)
+	if fields == nil {
+		utils.WriteJSON(w, http.StatusOK, track)
+		return
+	}
+
+	resp, err := selectFields(track, fields)
+	if err != nil {
+		h.logError("Encoding track %d failed: %v", track.ID, err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// selectFields encodes v as a JSON object holding only the given top-level
+// fields. Fields v omits when empty stay omitted.
+func selectFields(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if raw, ok := all[name]; ok {
+			selected[name] = raw
+		}
+	}
+	return selected, nil
+}
+
+// fieldsParam parses the optional fields parameter into the list of track
+// fields to return, or nil for all of them. On failure it writes the error
+// response and returns false.
+func (h *Handlers) fieldsParam(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("fields"))
+	if raw == "" {
+		return nil, true
+	}
+	var fields []string
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !trackFields[name] {
+			h.logDebug("Unknown track field: %q", name)
+			writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Unknown field '"+name+"'")
+			return nil, false
+		}
+		fields = append(fields, name)
+	}
+	if fields == nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAM", "Invalid 'fields' parameter")
+		return nil, false
+	}
+	return fields, true
+}
+
+// jsonFields returns the JSON names of the exported fields of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
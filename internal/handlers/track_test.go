@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"testing"
+
+	"soundcloud-api/internal/config"
+	"soundcloud-api/internal/scclient"
+)
+
+func TestFieldsParam(t *testing.T) {
+	h := &Handlers{Cfg: &config.Config{}, Logger: log.New(io.Discard, "", 0)}
+	tests := []struct {
+		name     string
+		raw      string
+		want     []string
+		wantCode string
+	}{
+		{"absent", "", nil, ""},
+		{"single", "title", []string{"title"}, ""},
+		{"several with spaces", " id , title,artwork_url ", []string{"id", "title", "artwork_url"}, ""},
+		{"empty entries skipped", "id,,title,", []string{"id", "title"}, ""},
+		{"nested object", "publisher_metadata", []string{"publisher_metadata"}, ""},
+		{"whitespace only", "   ", nil, ""},
+		{"only separators", " , ,", nil, "INVALID_PARAM"},
+		{"unknown", "id,bogus", nil, "INVALID_PARAM"},
+		{"Go field name", "PlaybackCount", nil, "INVALID_PARAM"},
+		{"blocked", "track_authorization", nil, "INVALID_PARAM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/soundcloud/track?fields="+url.QueryEscape(tt.raw), nil)
+			w := httptest.NewRecorder()
+
+			got, ok := h.fieldsParam(w, r)
+			if tt.wantCode != "" {
+				var body map[string]string
+				_ = json.Unmarshal(w.Body.Bytes(), &body)
+				if ok || w.Code != http.StatusBadRequest || body["error_code"] != tt.wantCode {
+					t.Fatalf("ok = %v, status = %d, body = %v, want %s", ok, w.Code, body, tt.wantCode)
+				}
+				return
+			}
+			if !ok || !slices.Equal(got, tt.want) {
+				t.Fatalf("fieldsParam = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	track := &scclient.Track{
+		ID:            1,
+		Title:         "Demo",
+		PlaybackCount: 42,
+	}
+	tests := []struct {
+		name   string
+		fields []string
+		want   map[string]string
+	}{
+		{"selected only", []string{"id", "title"}, map[string]string{"id": "1", "title": `"Demo"`}},
+		{"zero values kept", []string{"likes_count", "description"}, map[string]string{"likes_count": "0", "description": `""`}},
+		{"omitted when empty", []string{"id", "publisher_metadata", "user"}, map[string]string{"id": "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectFields(track, tt.fields)
+			if err != nil {
+				t.Fatalf("selectFields returned error: %v", err)
+			}
+			gotStr := make(map[string]string, len(got))
+			for k, v := range got {
+				gotStr[k] = string(v)
+			}
+			if !reflect.DeepEqual(gotStr, tt.want) {
+				t.Fatalf("selectFields = %v, want %v", gotStr, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	type sample struct {
+		Plain    string
+		Named    string `json:"named"`
+		Options  string `json:"opts,omitempty"`
+		Skipped  string `json:"-"`
+		Untagged int    `json:",omitempty"`
+		hidden   string
+	}
+	_ = sample{}.hidden
+
+	got := jsonFields(reflect.TypeFor[sample]())
+	want := map[string]bool{"Plain": true, "named": true, "opts": true, "Untagged": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("jsonFields = %v, want %v", got, want)
+	}
+
+	for _, name := range []string{"playback_count", "likes_count", "tag_list", "license", "publisher_metadata", "monetization_model"} {
+		if !trackFields[name] {
+			t.Fatalf("trackFields lacks %q", name)
+		}
+	}
+	if trackFields["track_authorization"] {
+		t.Fatal("trackFields allows track_authorization")
+	}
+}
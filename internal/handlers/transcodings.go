@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+func (h *Handlers) TranscodingsHandler(w http.ResponseWriter, r *http.Request) {
+	trackURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+
+	h.logRequest(r, trackURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	track, err := h.ScClient.ResolveTrack(ctx, trackURL)
+	if err != nil {
+		h.writeClientError(w, err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+		return
+	}
+
+	resp := &types.TranscodingsResponse{
+		TrackID:      track.ID,
+		Policy:       track.Policy,
+		TrackInfo:    newTrackInfo(track),
+		Transcodings: make([]types.TranscodingInfo, 0, len(track.Media.Transcodings)),
+	}
+	for i := range track.Media.Transcodings {
+		t := &track.Media.Transcodings[i]
+		resp.Transcodings = append(resp.Transcodings, types.TranscodingInfo{
+			Protocol: t.Format.Protocol,
+			MimeType: t.Format.MimeType,
+			Format:   t.FormatName(),
+			Quality:  t.Quality,
+			Preset:   t.Preset,
+			Duration: t.Duration,
+			Snippet:  t.Snippet,
+			Legacy:   t.IsLegacy,
+			Playable: t.Playable(),
+		})
+	}
+
+	h.logDebug("Track %d has %d transcodings", track.ID, len(resp.Transcodings))
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"soundcloud-api/internal/scclient"
+	"soundcloud-api/internal/utils"
+	"soundcloud-api/pkg/types"
+)
+
+func (h *Handlers) UserHandler(w http.ResponseWriter, r *http.Request) {
+	profileURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+
+	h.logRequest(r, profileURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	user, err := h.ScClient.ResolveUser(ctx, profileURL)
+	if err != nil {
+		h.writeClientError(w, err, "USER_NOT_FOUND", "User not found or unavailable")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, newUserInfo(user))
+}
+
+func (h *Handlers) UserTracksHandler(w http.ResponseWriter, r *http.Request) {
+	profileURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+	page, ok := h.pageOptions(w, r)
+	if !ok {
+		return
+	}
+
+	h.logRequest(r, profileURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	user, err := h.ScClient.ResolveUser(ctx, profileURL)
+	if err != nil {
+		h.writeClientError(w, err, "USER_NOT_FOUND", "User not found or unavailable")
+		return
+	}
+
+	tracks, err := h.ScClient.GetUserTracks(ctx, user.ID, page)
+	if err != nil {
+		h.writeClientError(w, err, "UPSTREAM_ERROR", "Failed to load user tracks")
+		return
+	}
+
+	resp := &types.UserTracksResponse{
+		User:       newUserInfo(user),
+		Tracks:     make([]types.TrackInfo, 0, len(tracks.Collection)),
+		NextCursor: tracks.NextCursor(),
+	}
+	for i := range tracks.Collection {
+		resp.Tracks = append(resp.Tracks, *newTrackInfo(&tracks.Collection[i]))
+	}
+
+	h.logDebug("User %d: returned %d tracks", user.ID, len(resp.Tracks))
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handlers) UserLikesHandler(w http.ResponseWriter, r *http.Request) {
+	h.userItems(w, r, "likes", func(ctx context.Context, userID int64, page scclient.PageOptions) ([]types.UserItem, string, error) {
+		likes, err := h.ScClient.GetUserLikes(ctx, userID, page)
+		if err != nil {
+			return nil, "", err
+		}
+		items := make([]types.UserItem, 0, len(likes.Collection))
+		for _, like := range likes.Collection {
+			items = append(items, newUserItem(like.CreatedAt, like.Track, like.Playlist))
+		}
+		return items, likes.NextCursor(), nil
+	})
+}
+
+func (h *Handlers) UserRepostsHandler(w http.ResponseWriter, r *http.Request) {
+	h.userItems(w, r, "reposts", func(ctx context.Context, userID int64, page scclient.PageOptions) ([]types.UserItem, string, error) {
+		reposts, err := h.ScClient.GetUserReposts(ctx, userID, page)
+		if err != nil {
+			return nil, "", err
+		}
+		items := make([]types.UserItem, 0, len(reposts.Collection))
+		for _, repost := range reposts.Collection {
+			items = append(items, newUserItem(repost.CreatedAt, repost.Track, repost.Playlist))
+		}
+		return items, reposts.NextCursor(), nil
+	})
+}
+
+// userItems resolves the profile URL and writes one page of the collection
+// returned by fetch.
+func (h *Handlers) userItems(w http.ResponseWriter, r *http.Request, name string, fetch func(context.Context, int64, scclient.PageOptions) ([]types.UserItem, string, error)) {
+	profileURL, ok := h.urlParam(w, r)
+	if !ok {
+		return
+	}
+	page, ok := h.pageOptions(w, r)
+	if !ok {
+		return
+	}
+
+	h.logRequest(r, profileURL)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Cfg.RequestTimeout)
+	defer cancel()
+
+	user, err := h.ScClient.ResolveUser(ctx, profileURL)
+	if err != nil {
+		h.writeClientError(w, err, "USER_NOT_FOUND", "User not found or unavailable")
+		return
+	}
+
+	items, nextCursor, err := fetch(ctx, user.ID, page)
+	if err != nil {
+		h.writeClientError(w, err, "UPSTREAM_ERROR", "Failed to load user "+name)
+		return
+	}
+
+	h.logDebug("User %d: returned %d %s", user.ID, len(items), name)
+	utils.WriteJSON(w, http.StatusOK, &types.UserItemsResponse{
+		User:       newUserInfo(user),
+		Items:      items,
+		NextCursor: nextCursor,
+	})
+}
+
+func newUserItem(createdAt string, track *scclient.Track, playlist *scclient.Playlist) types.UserItem {
+	item := types.UserItem{CreatedAt: createdAt}
+	switch {
+	case track != nil:
+		item.Kind = "track"
+		item.Track = newTrackInfo(track)
+	case playlist != nil:
+		item.Kind = "playlist"
+		item.Playlist = newPlaylistInfo(playlist)
+	}
+	return item
+}
+
+// pageOptions reads the limit and cursor query parameters.
+func (h *Handlers) pageOptions(w http.ResponseWriter, r *http.Request) (scclient.PageOptions, bool) {
+	limit, ok := h.intParam(w, r, "limit", 0)
+	if !ok {
+		return scclient.PageOptions{}, false
+	}
+	return scclient.PageOptions{
+		Limit:  limit,
+		Cursor: r.URL.Query().Get("cursor"),
+	}, true
+}
+
+func newUserInfo(u *scclient.User) *types.UserInfo {
+	return &types.UserInfo{
+		ID:              u.ID,
+		Username:        u.Username,
+		FullName:        u.FullName,
+		PermalinkURL:    u.PermalinkURL,
+		AvatarURL:       u.AvatarURL,
+		City:            u.City,
+		CountryCode:     u.CountryCode,
+		Description:     u.Description,
+		FollowersCount:  u.FollowersCount,
+		FollowingsCount: u.FollowingsCount,
+		TrackCount:      u.TrackCount,
+		PlaylistCount:   u.PlaylistCount,
+		LikesCount:      u.LikesCount,
+		Verified:        u.Verified,
+	}
+}
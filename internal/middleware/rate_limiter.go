@@ -55,34 +55,42 @@ func (r *RateLimiter) Stop() {
 }
 
 func (r *RateLimiter) IsRateLimited(clientID string) (bool, *types.RateLimitResponse) {
+	return r.IsRateLimitedN(clientID, 1)
+}
+
+// IsRateLimitedN is IsRateLimited for a request that counts as n requests,
+// e.g. a batch of n items. Nothing is counted when it is limited.
+func (r *RateLimiter) IsRateLimitedN(clientID string, n int) (bool, *types.RateLimitResponse) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	now := time.Now()
-	if info, ok := r.clients[clientID]; ok {
-		if now.After(info.ResetTime) {
-			info.Count = 1
-			info.ResetTime = now.Add(r.window)
-			return false, nil
-		}
-		if info.Count >= r.max {
-			return true, &types.RateLimitResponse{
-				Error: "Rate limit exceeded",
-				Details: map[string]interface{}{
-					"limit":          r.max,
-					"window_seconds": int(r.window.Seconds()),
-					"reset_time":     info.ResetTime.Format(time.RFC3339),
-				},
-			}
-		}
-		info.Count++
-		return false, nil
+	info, ok := r.clients[clientID]
+	if !ok {
+		info = &types.RateInfo{ResetTime: now.Add(r.window)}
+		r.clients[clientID] = info
+	} else if now.After(info.ResetTime) {
+		info.Count = 0
+		info.ResetTime = now.Add(r.window)
 	}
 
-	r.clients[clientID] = &types.RateInfo{
-		Count:     1,
-		ResetTime: now.Add(r.window),
+	// A single request opening a window is always let through.
+	if info.Count+n > r.max && (info.Count > 0 || n > 1) {
+		details := map[string]interface{}{
+			"limit":          r.max,
+			"window_seconds": int(r.window.Seconds()),
+			"reset_time":     info.ResetTime.Format(time.RFC3339),
+		}
+		if n > 1 {
+			details["requested"] = n
+			details["remaining"] = max(r.max-info.Count, 0)
+		}
+		return true, &types.RateLimitResponse{
+			Error:   "Rate limit exceeded",
+			Details: details,
+		}
 	}
+	info.Count += n
 	return false, nil
 }
 
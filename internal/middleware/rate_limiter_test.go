@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(t *testing.T, max int, window time.Duration) *RateLimiter {
+	t.Helper()
+	rl := NewRateLimiter(max, window)
+	t.Cleanup(rl.Stop)
+	return rl
+}
+
+func TestIsRateLimitedN_SingleRequestsMatchIsRateLimited(t *testing.T) {
+	rl := newTestLimiter(t, 3, time.Hour)
+
+	for i := range 3 {
+		if limited, _ := rl.IsRateLimitedN("a", 1); limited {
+			t.Fatalf("request %d limited, want allowed", i+1)
+		}
+	}
+	limited, resp := rl.IsRateLimitedN("a", 1)
+	if !limited {
+		t.Fatal("4th request allowed, want limited")
+	}
+	if resp.Details["limit"] != 3 {
+		t.Fatalf("details = %v, want limit 3", resp.Details)
+	}
+	if _, ok := resp.Details["requested"]; ok {
+		t.Fatalf("details = %v, want no batch fields for a single request", resp.Details)
+	}
+	if limited, _ := rl.IsRateLimited("b"); limited {
+		t.Fatal("other client limited")
+	}
+
+	// A limit of 0 still lets the first request of a window through, as
+	// IsRateLimited always did.
+	zero := newTestLimiter(t, 0, time.Hour)
+	if limited, _ := zero.IsRateLimitedN("a", 1); limited {
+		t.Fatal("first request limited with max 0")
+	}
+	if limited, _ := zero.IsRateLimitedN("a", 1); !limited {
+		t.Fatal("second request allowed with max 0")
+	}
+}
+
+func TestIsRateLimitedN_RejectsOversizedBatchWithoutCharging(t *testing.T) {
+	rl := newTestLimiter(t, 5, time.Hour)
+
+	if limited, _ := rl.IsRateLimitedN("a", 3); limited {
+		t.Fatal("batch of 3 limited, want allowed")
+	}
+	limited, resp := rl.IsRateLimitedN("a", 3)
+	if !limited {
+		t.Fatal("batch of 3 allowed with 2 remaining, want limited")
+	}
+	if resp.Details["requested"] != 3 || resp.Details["remaining"] != 2 {
+		t.Fatalf("details = %v, want requested 3, remaining 2", resp.Details)
+	}
+
+	// The rejected batch didn't use up the remaining allowance.
+	if limited, _ := rl.IsRateLimitedN("a", 2); limited {
+		t.Fatal("batch of 2 limited after rejected batch, want allowed")
+	}
+	if limited, _ := rl.IsRateLimitedN("a", 1); !limited {
+		t.Fatal("request allowed past the limit")
+	}
+}
+
+func TestIsRateLimitedN_ResetsAfterWindow(t *testing.T) {
+	rl := newTestLimiter(t, 2, 20*time.Millisecond)
+
+	if limited, _ := rl.IsRateLimitedN("a", 2); limited {
+		t.Fatal("batch of 2 limited, want allowed")
+	}
+	if limited, _ := rl.IsRateLimitedN("a", 1); !limited {
+		t.Fatal("request allowed past the limit")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if limited, _ := rl.IsRateLimitedN("a", 2); limited {
+		t.Fatal("batch of 2 limited in a new window, want allowed")
+	}
+}
@@ -0,0 +1,181 @@
+package scclient
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of the client's circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets all requests through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails all requests with UPSTREAM_UNAVAILABLE.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe requests through to
+	// find out whether upstream has recovered.
+	BreakerHalfOpen
+)
+
+func (st BreakerState) String() string {
+	switch st {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig controls the circuit breaker. The breaker opens once at least
+// MinRequests were made within Window and the share of failures among them
+// reaches FailureRatio. Connection errors, 5xx responses and calls slower than
+// SlowCall count as failures. After OpenTimeout it lets HalfOpenProbes
+// requests through; it closes again if they all succeed. A FailureRatio of 0
+// disables the breaker.
+type BreakerConfig struct {
+	Window         time.Duration
+	MinRequests    int
+	FailureRatio   float64
+	SlowCall       time.Duration
+	OpenTimeout    time.Duration
+	HalfOpenProbes int
+}
+
+var DefaultBreakerConfig = BreakerConfig{
+	Window:         30 * time.Second,
+	MinRequests:    10,
+	FailureRatio:   0.5,
+	SlowCall:       10 * time.Second,
+	OpenTimeout:    30 * time.Second,
+	HalfOpenProbes: 1,
+}
+
+// WithBreaker replaces DefaultBreakerConfig.
+func WithBreaker(cfg BreakerConfig) Option {
+	return func(s *SoundCloudClient) {
+		s.breaker = newBreaker(cfg)
+	}
+}
+
+// BreakerStatus is a snapshot of the circuit breaker.
+type BreakerStatus struct {
+	State    BreakerState
+	Requests int
+	Failures int
+	// OpenUntil is when an open breaker starts letting probes through.
+	OpenUntil time.Time
+}
+
+// BreakerStatus returns the current state of the circuit breaker.
+func (s *SoundCloudClient) BreakerStatus() BreakerStatus {
+	b := s.breaker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st := BreakerStatus{State: b.state, Requests: b.requests, Failures: b.failures}
+	if b.state == BreakerOpen {
+		st.OpenUntil = b.openedAt.Add(b.cfg.OpenTimeout)
+	}
+	return st
+}
+
+type callOutcome int
+
+const (
+	callSuccess callOutcome = iota
+	callFailure
+	// callIgnored releases a half-open probe without changing state, e.g.
+	// when the caller cancelled the request.
+	callIgnored
+)
+
+type breaker struct {
+	cfg BreakerConfig
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	probesOK    int
+}
+
+func newBreaker(cfg BreakerConfig) *breaker {
+	return &breaker{cfg: cfg, windowStart: time.Now()}
+}
+
+// allow reports whether a request may be sent. Every allowed request must be
+// followed by a call to record.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probes, b.probesOK = 0, 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= max(b.cfg.HalfOpenProbes, 1) {
+			return false
+		}
+		b.probes++
+	}
+	return true
+}
+
+func (b *breaker) record(outcome callOutcome, latency time.Duration) {
+	if outcome == callSuccess && b.cfg.SlowCall > 0 && latency > b.cfg.SlowCall {
+		outcome = callFailure
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		switch outcome {
+		case callFailure:
+			b.open()
+		case callSuccess:
+			b.probesOK++
+			if b.probesOK >= max(b.cfg.HalfOpenProbes, 1) {
+				b.state = BreakerClosed
+				b.resetWindow()
+			}
+		default:
+			b.probes--
+		}
+	case BreakerClosed:
+		if outcome == callIgnored {
+			return
+		}
+		if time.Since(b.windowStart) > b.cfg.Window {
+			b.resetWindow()
+		}
+		b.requests++
+		if outcome == callFailure {
+			b.failures++
+		}
+		if b.cfg.FailureRatio > 0 && b.requests >= b.cfg.MinRequests && float64(b.failures) >= b.cfg.FailureRatio*float64(b.requests) {
+			b.open()
+		}
+	}
+}
+
+func (b *breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+}
+
+func (b *breaker) resetWindow() {
+	b.windowStart = time.Now()
+	b.requests, b.failures = 0, 0
+}
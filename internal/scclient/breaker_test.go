@@ -0,0 +1,101 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreaker_OpensOnFailureRatioAndRecovers(t *testing.T) {
+	b := newBreaker(BreakerConfig{Window: time.Minute, MinRequests: 4, FailureRatio: 0.5, OpenTimeout: 10 * time.Millisecond, HalfOpenProbes: 1})
+
+	for _, outcome := range []callOutcome{callSuccess, callFailure, callSuccess} {
+		if !b.allow() {
+			t.Fatal("closed breaker rejected a request")
+		}
+		b.record(outcome, 0)
+	}
+	if b.state != BreakerClosed {
+		t.Fatalf("state = %s before MinRequests, want closed", b.state)
+	}
+
+	b.allow()
+	b.record(callFailure, 0)
+	if b.state != BreakerOpen {
+		t.Fatalf("state = %s at 2/4 failures, want open", b.state)
+	}
+	if b.allow() {
+		t.Fatal("open breaker allowed a request")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker didn't let a probe through after OpenTimeout")
+	}
+	if b.state != BreakerHalfOpen || b.allow() {
+		t.Fatalf("state = %s, want half-open with a single probe", b.state)
+	}
+	b.record(callSuccess, 0)
+	if b.state != BreakerClosed {
+		t.Fatalf("state = %s after successful probe, want closed", b.state)
+	}
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b := newBreaker(BreakerConfig{Window: time.Minute, MinRequests: 1, FailureRatio: 1, OpenTimeout: time.Millisecond, HalfOpenProbes: 1})
+	b.allow()
+	b.record(callFailure, 0)
+
+	time.Sleep(2 * time.Millisecond)
+	b.allow()
+	b.record(callIgnored, 0)
+	if b.state != BreakerHalfOpen || !b.allow() {
+		t.Fatalf("state = %s, want half-open with the ignored probe released", b.state)
+	}
+	b.record(callFailure, 0)
+	if b.state != BreakerOpen {
+		t.Fatalf("state = %s after failed probe, want open", b.state)
+	}
+}
+
+func TestBreaker_SlowCallsCountAsFailures(t *testing.T) {
+	b := newBreaker(BreakerConfig{Window: time.Minute, MinRequests: 1, FailureRatio: 1, SlowCall: time.Second, OpenTimeout: time.Minute})
+	b.allow()
+	b.record(callSuccess, 2*time.Second)
+	if b.state != BreakerOpen {
+		t.Fatalf("state = %s after slow call, want open", b.state)
+	}
+}
+
+func TestResolveTrack_FailsFastWhileBreakerIsOpen(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithBreaker(BreakerConfig{Window: time.Minute, MinRequests: 2, FailureRatio: 0.5, OpenTimeout: time.Minute}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+
+	_, err := c.GetStreamURL(context.Background(), "https://soundcloud.com/a/b", StreamOptions{})
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "UPSTREAM_UNAVAILABLE" {
+		t.Fatalf("err = %v, want UPSTREAM_UNAVAILABLE", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+	if st := c.BreakerStatus(); st.State != BreakerOpen || st.OpenUntil.IsZero() {
+		t.Fatalf("BreakerStatus = %+v, want open", st)
+	}
+}
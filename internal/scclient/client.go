@@ -4,199 +4,455 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+)
 
-	"soundcloud-api/internal/utils"
+const (
+	DefaultBaseURL   = "https://api-v2.soundcloud.com"
+	DefaultUserAgent = "Go-http-client/1.1"
 )
 
 type SoundCloudClient struct {
 	httpClient *http.Client
-	authToken  string
-	clientID   string
+	transport  http.RoundTripper
+	baseURL    string
+	webURL     string
+	userAgent  string
+	logger     *log.Logger
+	debug      bool
+
+	creds   *credentialPool
+	oauth   *oauthRefresher
+	retry   RetryPolicy
+	breaker *breaker
+
+	discoverClientID bool
+	discoverMu       sync.Mutex
+	lastDiscovery    time.Time
+}
+
+// Option configures a SoundCloudClient.
+type Option func(*SoundCloudClient)
+
+// WithBaseURL points the client at a different api-v2 compatible host, e.g. a
+// mock server or a caching proxy. Empty values are ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(s *SoundCloudClient) {
+		if baseURL != "" {
+			s.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithHTTPClient replaces the http.Client used for all requests. The timeout
+// passed to New is not applied to it. The client itself is never modified; see
+// WithTransport.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *SoundCloudClient) {
+		if c != nil {
+			s.httpClient = c
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used for all requests. It is applied
+// after every other option, to a copy of the http.Client, so it combines with
+// WithHTTPClient in either order without changing the caller's client.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(s *SoundCloudClient) {
+		if rt != nil {
+			s.transport = rt
+		}
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent upstream. Empty values are
+// ignored.
+func WithUserAgent(userAgent string) Option {
+	return func(s *SoundCloudClient) {
+		if userAgent != "" {
+			s.userAgent = userAgent
+		}
+	}
+}
+
+// WithWebURL sets the soundcloud.com web app URL used for client_id discovery.
+// Empty values are ignored.
+func WithWebURL(webURL string) Option {
+	return func(s *SoundCloudClient) {
+		if webURL != "" {
+			s.webURL = strings.TrimRight(webURL, "/")
+		}
+	}
+}
+
+// WithClientIDDiscovery makes the client fetch a fresh client_id from the web
+// app when /resolve rejects the current one with 401 or 403.
+func WithClientIDDiscovery(enabled bool) Option {
+	return func(s *SoundCloudClient) {
+		s.discoverClientID = enabled
+	}
+}
+
+// WithLogger sets where the client reports background events such as client_id
+// rotation. Debug messages are only written when debug is true.
+func WithLogger(logger *log.Logger, debug bool) Option {
+	return func(s *SoundCloudClient) {
+		s.logger = logger
+		s.debug = debug
+	}
 }
 
-func New(authToken, clientID string, timeout time.Duration) *SoundCloudClient {
+func New(authToken, clientID string, timeout time.Duration, opts ...Option) *SoundCloudClient {
 	client := &http.Client{
 		Timeout: timeout,
 	}
-	return &SoundCloudClient{
+	s := &SoundCloudClient{
 		httpClient: client,
-		authToken:  authToken,
-		clientID:   clientID,
+		creds:      newCredentialPool(Credential{AuthToken: authToken, ClientID: clientID}),
+		retry:      DefaultRetryPolicy,
+		breaker:    newBreaker(DefaultBreakerConfig),
+		baseURL:    DefaultBaseURL,
+		webURL:     DefaultWebURL,
+		userAgent:  DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.transport != nil {
+		c := *s.httpClient
+		c.Transport = s.transport
+		s.httpClient = &c
+	}
+	if s.oauth != nil {
+		s.oauth.init(s)
 	}
+	return s
 }
 
+// ClientID returns the client_id of the primary credential.
+func (s *SoundCloudClient) ClientID() string {
+	s.creds.mu.Lock()
+	defer s.creds.mu.Unlock()
+	return s.creds.primary().clientID
+}
+
+// setClientID swaps in a discovered client_id for the primary credential and
+// every other credential that shared its client_id or had none.
+func (s *SoundCloudClient) setClientID(clientID string) {
+	s.creds.mu.Lock()
+	defer s.creds.mu.Unlock()
+	stale := s.creds.primary().clientID
+	for _, c := range s.creds.creds {
+		if c.clientID == stale || c.clientID == "" {
+			c.clientID = clientID
+		}
+	}
+}
+
+// AuthToken returns the OAuth access token of the primary credential.
+func (s *SoundCloudClient) AuthToken() string {
+	s.creds.mu.Lock()
+	defer s.creds.mu.Unlock()
+	return s.creds.primary().authToken
+}
+
+// SetAuthToken swaps the OAuth access token of the primary credential.
+func (s *SoundCloudClient) SetAuthToken(token string) {
+	s.creds.mu.Lock()
+	defer s.creds.mu.Unlock()
+	s.creds.primary().authToken = token
+}
+
+func (s *SoundCloudClient) logDebug(format string, v ...interface{}) {
+	if s.logger != nil && s.debug {
+		s.logger.Printf("[DEBUG] "+format, v...)
+	}
+}
+
+func (s *SoundCloudClient) logInfo(format string, v ...interface{}) {
+	if s.logger != nil {
+		s.logger.Printf("[INFO] "+format, v...)
+	}
+}
+
+func (s *SoundCloudClient) endpoint(path string) string {
+	return s.baseURL + path
+}
+
+// rebase rewrites absolute api-v2 URLs handed out by SoundCloud (such as
+// transcoding URLs) so they go through the configured base URL as well.
+func (s *SoundCloudClient) rebase(u *url.URL) {
+	if s.baseURL == DefaultBaseURL {
+		return
+	}
+	def, _ := url.Parse(DefaultBaseURL)
+	if u.Host != def.Host {
+		return
+	}
+	base, err := url.Parse(s.baseURL)
+	if err != nil {
+		return
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.Path = strings.TrimRight(base.Path, "/") + u.Path
+	u.RawPath = ""
+}
+
+// doRequest sends req. GETs are retried on transient failures according to
+// the client's RetryPolicy.
 func (s *SoundCloudClient) doRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
-	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "Go-http-client/1.1")
-	req.Header.Set("Accept", "application/json")
-	if s.authToken != "" {
-		req.Header.Set("Authorization", "OAuth "+s.authToken)
+	if req.Method != http.MethodGet || req.Body != nil {
+		return s.attempt(ctx, req)
 	}
-	return s.httpClient.Do(req)
+	return s.doWithRetry(ctx, req)
 }
 
-func (s *SoundCloudClient) ValidateToken(ctx context.Context) (bool, string) {
-	u := "https://api-v2.soundcloud.com/me"
-	req, _ := http.NewRequest("GET", u, nil)
-	resp, err := s.doRequest(ctx, req)
+// attempt sends req through the circuit breaker, failing with
+// UPSTREAM_UNAVAILABLE right away while it is open.
+func (s *SoundCloudClient) attempt(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if !s.breaker.allow() {
+		return nil, errUpstreamUnavailable
+	}
+	start := time.Now()
+	resp, err := s.sendRotating(ctx, req)
+
+	outcome := callSuccess
+	switch {
+	case err != nil && ctx.Err() != nil:
+		outcome = callIgnored
+	case err != nil || resp.StatusCode >= 500:
+		outcome = callFailure
+	}
+	s.breaker.record(outcome, time.Since(start))
+	return resp, err
+}
+
+// sendRotating sends req with the next available credential. If upstream
+// rejects that credential it is put on cool-down and the request is retried
+// once with another one.
+func (s *SoundCloudClient) sendRotating(ctx context.Context, req *http.Request) (*http.Response, error) {
+	cred := s.creds.pick(nil)
+	resp, err := s.sendWithRefresh(ctx, req, cred)
 	if err != nil {
-		return false, err.Error()
+		return nil, err
+	}
+	s.creds.report(cred, resp)
+	if !isCredentialFailure(resp.StatusCode) || req.Body != nil {
+		return resp, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 200 {
-		return true, ""
+	other := s.creds.pick(cred)
+	if other == nil {
+		return resp, nil
 	}
-	body, _ := io.ReadAll(resp.Body)
-	return false, "status code " + strconv.Itoa(resp.StatusCode) + ": " + string(body)
+	s.logDebug("Credential %d got %d, retrying with credential %d", cred.index, resp.StatusCode, other.index)
+	resp.Body.Close()
+	resp, err = s.send(ctx, req, other)
+	if err != nil {
+		return nil, err
+	}
+	s.creds.report(other, resp)
+	return resp, nil
+}
+
+// sendWithRefresh is send, except that a 401 on the primary credential
+// triggers an OAuth refresh and one more attempt when refresh is configured.
+func (s *SoundCloudClient) sendWithRefresh(ctx context.Context, req *http.Request, cred *credential) (*http.Response, error) {
+	resp, usedToken, err := s.sendAs(ctx, req, cred)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || s.oauth == nil || cred != s.creds.primary() || req.Body != nil {
+		return resp, err
+	}
+
+	// The access token may have expired early or been revoked; get a new one
+	// and try once more.
+	if rerr := s.oauth.refreshAfterUnauthorized(ctx, usedToken); rerr != nil {
+		s.logInfo("OAuth token refresh after 401 failed: %v", rerr)
+		return resp, nil
+	}
+	resp.Body.Close()
+	return s.send(ctx, req, cred)
+}
+
+func (s *SoundCloudClient) send(ctx context.Context, req *http.Request, cred *credential) (*http.Response, error) {
+	resp, _, err := s.sendAs(ctx, req, cred)
+	return resp, err
+}
+
+// sendAs sends a copy of req authenticated as cred and returns the auth token
+// it used.
+func (s *SoundCloudClient) sendAs(ctx context.Context, req *http.Request, cred *credential) (*http.Response, string, error) {
+	authToken, clientID := s.creds.get(cred)
+
+	r := req.Clone(ctx)
+	r.Header.Set("User-Agent", s.userAgent)
+	r.Header.Set("Accept", "application/json")
+	if authToken != "" {
+		r.Header.Set("Authorization", "OAuth "+authToken)
+	}
+	if clientID != "" {
+		q := r.URL.Query()
+		q.Set("client_id", clientID)
+		r.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := s.httpClient.Do(r)
+	return resp, authToken, err
+}
+
+// ValidateToken checks the primary credential against /me.
+func (s *SoundCloudClient) ValidateToken(ctx context.Context) (bool, string) {
+	return s.checkCredential(ctx, s.creds.primary())
 }
 
-func (s *SoundCloudClient) ResolveTrack(ctx context.Context, trackURL string) (map[string]interface{}, error) {
-	resolveURL := "https://api-v2.soundcloud.com/resolve"
-	req, _ := http.NewRequest("GET", resolveURL, nil)
-	q := req.URL.Query()
-	q.Set("url", trackURL)
-	q.Set("client_id", s.clientID)
-	req.URL.RawQuery = q.Encode()
+// getJSON performs a GET against the API base URL and decodes a 200 response
+// into v.
+func (s *SoundCloudClient) getJSON(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u, _ := url.Parse(s.endpoint(path))
+	u.RawQuery = query.Encode()
+	return s.getURL(ctx, u, v)
+}
 
+// getURL is getJSON for absolute api-v2 URLs, such as next_href links.
+func (s *SoundCloudClient) getURL(ctx context.Context, u *url.URL, v interface{}) error {
+	s.rebase(u)
+	req, _ := http.NewRequest("GET", u.String(), nil)
 	resp, err := s.doRequest(ctx, req)
 	if err != nil {
-		return nil, err
+		var scErr *Error
+		if errors.As(err, &scErr) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrUpstream, err)
 	}
 	defer resp.Body.Close()
 
+	op := strings.TrimPrefix(u.Path, "/")
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != 200 {
-		return nil, errors.New("resolve failed: " + strconv.Itoa(resp.StatusCode))
+		return &UpstreamError{Op: op, StatusCode: resp.StatusCode}
 	}
 
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return nil, err
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: decoding %s: %w", ErrUpstream, op, err)
 	}
-	return parsed, nil
+	return nil
 }
 
-func (s *SoundCloudClient) GetStreamURL(ctx context.Context, trackURL string) (map[string]interface{}, error) {
-	trackInfo, err := s.ResolveTrack(ctx, trackURL)
-	if err != nil || trackInfo == nil {
-		return map[string]interface{}{
-			"error":      "Track not found or unavailable",
-			"stream_url": nil,
-			"error_code": "TRACK_NOT_FOUND",
-		}, nil
+// resolve looks up rawURL via /resolve. The secret token of a private share
+// link is passed along explicitly as well.
+func (s *SoundCloudClient) resolve(ctx context.Context, rawURL string, v interface{}) error {
+	query := url.Values{"url": {rawURL}}
+	if _, token := SplitSecretToken(rawURL); token != "" {
+		query.Set("secret_token", token)
 	}
+	err := s.getJSON(ctx, "/resolve", query, v)
 
-	if policyRaw, ok := trackInfo["policy"]; ok {
-		if policyStr, ok := policyRaw.(string); ok && strings.ToUpper(policyStr) == "BLOCK" {
-			return map[string]interface{}{
-				"error":      "Track is blocked in your region",
-				"stream_url": nil,
-				"error_code": "GEO_BLOCKED",
-			}, nil
-		}
+	if !s.discoverClientID || !errors.Is(err, ErrUnauthorized) {
+		return err
 	}
 
-	media, _ := trackInfo["media"].(map[string]interface{})
-	transcodings, _ := media["transcodings"].([]interface{})
-	var progressiveURL string
-	for _, t := range transcodings {
-		if tm, ok := t.(map[string]interface{}); ok {
-			format, _ := tm["format"].(map[string]interface{})
-			if proto, _ := format["protocol"].(string); proto == "progressive" {
-				if u, _ := tm["url"].(string); u != "" {
-					progressiveURL = u
-					break
-				}
-			}
-		}
+	stale := s.ClientID()
+	if fresh, derr := s.RefreshClientID(ctx); derr != nil || fresh == stale {
+		return err
 	}
+	return s.getJSON(ctx, "/resolve", query, v)
+}
 
-	if progressiveURL == "" {
-		return map[string]interface{}{
-			"error":      "Progressive stream not available for this track",
-			"stream_url": nil,
-			"error_code": "NO_PROGRESSIVE_STREAM",
-		}, nil
+func (s *SoundCloudClient) ResolveTrack(ctx context.Context, trackURL string) (*Track, error) {
+	var track Track
+	if err := s.resolve(ctx, trackURL, &track); err != nil {
+		return nil, err
+	}
+	if track.Kind != "" && track.Kind != "track" {
+		return nil, newError("NOT_A_TRACK", "URL points to a "+track.Kind+", not a track")
 	}
+	if _, token := SplitSecretToken(trackURL); token != "" && track.SecretToken == "" {
+		// Needed again for the stream request.
+		track.SecretToken = token
+	}
+	return &track, nil
+}
 
-	u, err := url.Parse(progressiveURL)
+func (s *SoundCloudClient) GetStreamURL(ctx context.Context, trackURL string, opts StreamOptions) (*StreamResult, error) {
+	track, err := s.ResolveTrack(ctx, trackURL)
 	if err != nil {
-		return map[string]interface{}{
-			"error":      "Internal error building stream URL",
-			"stream_url": nil,
-			"error_code": "INTERNAL_ERROR",
-		}, nil
+		return nil, Classify(err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+	}
+	return s.StreamTrack(ctx, track, opts)
+}
+
+// StreamTrack fetches a stream URL for an already resolved track.
+func (s *SoundCloudClient) StreamTrack(ctx context.Context, track *Track, opts StreamOptions) (*StreamResult, error) {
+	if strings.ToUpper(track.Policy) == "BLOCK" {
+		return nil, wrapError("GEO_BLOCKED", "Track is blocked in your region", ErrGeoBlocked)
 	}
 
+	if selectTranscoding(track.Media.Transcodings, StreamOptions{}) == nil {
+		return nil, wrapError("NO_TRANSCODING", "No playable stream available for this track", ErrNoTranscoding)
+	}
+	transcoding := selectTranscoding(track.Media.Transcodings, opts)
+	if transcoding == nil {
+		return nil, wrapError("NO_MATCHING_TRANSCODING", "No stream matches the requested format, protocol or quality", ErrNoTranscoding)
+	}
+
+	u, err := url.Parse(transcoding.URL)
+	if err != nil {
+		return nil, wrapError("INTERNAL_ERROR", "Internal error building stream URL", fmt.Errorf("%w: %w", ErrUpstream, err))
+	}
+
+	s.rebase(u)
 	q := u.Query()
-	q.Set("client_id", s.clientID)
+	if track.TrackAuthorization != "" {
+		q.Set("track_authorization", track.TrackAuthorization)
+	}
+	if track.SecretToken != "" {
+		q.Set("secret_token", track.SecretToken)
+	}
 	u.RawQuery = q.Encode()
 
 	req, _ := http.NewRequest("GET", u.String(), nil)
 	resp, err := s.doRequest(ctx, req)
 	if err != nil {
-		return map[string]interface{}{
-			"error":      "Network error: " + err.Error(),
-			"stream_url": nil,
-			"error_code": "NETWORK_ERROR",
-		}, nil
+		var scErr *Error
+		if errors.As(err, &scErr) {
+			return nil, scErr
+		}
+		// Classify leaves out the request URL, which carries the client_id
+		// and track authorization.
+		return nil, Classify(fmt.Errorf("%w: %w", ErrUpstream, err), "NETWORK_ERROR", "Network error")
 	}
 	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != 200 {
-		return map[string]interface{}{
-			"error":      "Stream API error: " + strconv.Itoa(resp.StatusCode),
-			"stream_url": nil,
-			"error_code": "API_ERROR_" + strconv.Itoa(resp.StatusCode),
-		}, nil
+		return nil, wrapError("API_ERROR_"+strconv.Itoa(resp.StatusCode), "Stream API error: "+strconv.Itoa(resp.StatusCode),
+			&UpstreamError{Op: "stream", StatusCode: resp.StatusCode})
 	}
 
-	var streamResp map[string]interface{}
+	var streamResp struct {
+		URL string `json:"url"`
+	}
 	if err := json.Unmarshal(body, &streamResp); err != nil {
-		return map[string]interface{}{
-			"error":      "Internal server error",
-			"stream_url": nil,
-			"error_code": "INTERNAL_ERROR",
-		}, nil
-	}
-
-	finalURL, _ := streamResp["url"].(string)
-	if finalURL == "" {
-		return map[string]interface{}{
-			"error":      "No stream URL in response",
-			"stream_url": nil,
-			"error_code": "NO_STREAM_URL",
-		}, nil
-	}
-
-	trackInfoTitle, _ := trackInfo["title"].(string)
-	userObj, _ := trackInfo["user"].(map[string]interface{})
-	username, _ := userObj["username"].(string)
-
-	return map[string]interface{}{
-		"stream_url": finalURL,
-		"error":      nil,
-		"error_code": nil,
-		"track_info": map[string]interface{}{
-			"title":         utils.IfString(trackInfoTitle, "Unknown"),
-			"artist":        utils.IfString(username, "Unknown"),
-			"duration":      trackInfo["duration"],
-			"permalink_url": trackInfo["permalink_url"],
-			"artwork_url":   trackInfo["artwork_url"],
-			"genre":         trackInfo["genre"],
-			"release_date":  trackInfo["release_date"],
-		},
-		"cache_info": map[string]interface{}{
-			"timestamp":   time.Now().UTC().Format(time.RFC3339),
-			"ttl_seconds": 3600,
-		},
+		return nil, wrapError("INTERNAL_ERROR", "Internal server error", fmt.Errorf("%w: decoding stream: %w", ErrUpstream, err))
+	}
+
+	if streamResp.URL == "" {
+		return nil, wrapError("NO_STREAM_URL", "No stream URL in response", ErrUpstream)
+	}
+
+	return &StreamResult{
+		URL:         streamResp.URL,
+		Track:       track,
+		Transcoding: transcoding,
 	}, nil
 }
@@ -0,0 +1,299 @@
+package scclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestResolveTrack_UsesBaseURLAndUserAgent(t *testing.T) {
+	var gotUA, gotAuth, gotClientID, gotURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/resolve" {
+			t.Errorf("path = %q, want /resolve", r.URL.Path)
+		}
+		gotUA = r.Header.Get("User-Agent")
+		gotAuth = r.Header.Get("Authorization")
+		gotClientID = r.URL.Query().Get("client_id")
+		gotURL = r.URL.Query().Get("url")
+		writeJSON(w, map[string]interface{}{
+			"id":    int64(42),
+			"kind":  "track",
+			"title": "Song",
+			"user":  map[string]interface{}{"username": "artist"},
+		})
+	}))
+
+	c := New("token", "cid", time.Second, WithBaseURL(srv.URL+"/"), WithUserAgent("test-agent/1.0"))
+	track, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/artist/song")
+	if err != nil {
+		t.Fatalf("ResolveTrack returned error: %v", err)
+	}
+
+	if track.ID != 42 || track.Title != "Song" || track.User == nil || track.User.Username != "artist" {
+		t.Fatalf("unexpected track: %+v", track)
+	}
+	if gotUA != "test-agent/1.0" {
+		t.Fatalf("User-Agent = %q, want %q", gotUA, "test-agent/1.0")
+	}
+	if gotAuth != "OAuth token" {
+		t.Fatalf("Authorization = %q, want %q", gotAuth, "OAuth token")
+	}
+	if gotClientID != "cid" {
+		t.Fatalf("client_id = %q, want %q", gotClientID, "cid")
+	}
+	if gotURL != "https://soundcloud.com/artist/song" {
+		t.Fatalf("url = %q, want track URL", gotURL)
+	}
+}
+
+func TestWithTransport_IsUsedForRequests(t *testing.T) {
+	var called bool
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("offline")
+	})
+
+	c := New("", "cid", time.Second, WithTransport(rt))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err == nil {
+		t.Fatal("expected error from transport")
+	}
+	if !called {
+		t.Fatal("custom transport was not used")
+	}
+}
+
+func TestWithTransport_LeavesSharedHTTPClientAlone(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("offline")
+	})
+	for _, order := range []string{"client first", "transport first"} {
+		shared := &http.Client{Timeout: time.Minute}
+		opts := []Option{WithHTTPClient(shared), WithTransport(rt)}
+		if order == "transport first" {
+			opts[0], opts[1] = opts[1], opts[0]
+		}
+
+		c := New("", "cid", time.Second, opts...)
+		if shared.Transport != nil {
+			t.Fatalf("%s: shared client's transport was replaced", order)
+		}
+		if c.httpClient == shared || c.httpClient.Transport == nil || c.httpClient.Timeout != time.Minute {
+			t.Fatalf("%s: client = %+v, want a copy of the shared client with the transport", order, c.httpClient)
+		}
+	}
+}
+
+func TestGetStreamURL_RebasesTranscodingURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"id":    int64(1),
+			"kind":  "track",
+			"title": "Song",
+			"media": map[string]interface{}{
+				"transcodings": []interface{}{
+					map[string]interface{}{
+						"url":    "https://api-v2.soundcloud.com/media/soundcloud:tracks:1/abc/stream/progressive",
+						"format": map[string]interface{}{"protocol": "progressive", "mime_type": "audio/mpeg"},
+					},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/media/soundcloud:tracks:1/abc/stream/progressive", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("client_id") != "cid" {
+			t.Errorf("client_id missing on stream request")
+		}
+		writeJSON(w, map[string]string{"url": "https://cf-media.sndcdn.com/abc.mp3"})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	result, err := c.GetStreamURL(context.Background(), "https://soundcloud.com/a/song", StreamOptions{})
+	if err != nil {
+		t.Fatalf("GetStreamURL returned error: %v", err)
+	}
+	if result.URL != "https://cf-media.sndcdn.com/abc.mp3" {
+		t.Fatalf("URL = %q", result.URL)
+	}
+	if result.Track == nil || result.Track.Title != "Song" {
+		t.Fatalf("unexpected track: %+v", result.Track)
+	}
+}
+
+func TestGetStreamURL_ReturnsTypedError(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"id": int64(1), "kind": "track", "policy": "BLOCK"})
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	_, err := c.GetStreamURL(context.Background(), "https://soundcloud.com/a/song", StreamOptions{})
+
+	var scErr *Error
+	if !errors.As(err, &scErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if scErr.Code != "GEO_BLOCKED" {
+		t.Fatalf("Code = %q, want GEO_BLOCKED", scErr.Code)
+	}
+}
+
+func TestSelectTranscoding_FallsBackToHLS(t *testing.T) {
+	transcodings := []Transcoding{
+		{URL: "enc", Format: Format{Protocol: "ctr-encrypted-hls"}},
+		{URL: "preview", Snippet: true, Format: Format{Protocol: "progressive"}},
+		{URL: "hls", Format: Format{Protocol: "hls", MimeType: "audio/mpeg"}},
+	}
+
+	got := selectTranscoding(transcodings, StreamOptions{})
+	if got == nil || got.URL != "hls" {
+		t.Fatalf("selectTranscoding = %+v, want hls transcoding", got)
+	}
+
+	if got := selectTranscoding(transcodings[:2], StreamOptions{}); got == nil || got.URL != "preview" {
+		t.Fatalf("selectTranscoding = %+v, want snippet fallback", got)
+	}
+
+	if got := selectTranscoding(transcodings[:1], StreamOptions{}); got != nil {
+		t.Fatalf("selectTranscoding = %+v, want nil", got)
+	}
+}
+
+func TestSelectTranscoding_HonoursPreferences(t *testing.T) {
+	transcodings := []Transcoding{
+		{URL: "mp3-prog", Preset: "mp3_1_0", Quality: "sq", Format: Format{Protocol: "progressive", MimeType: "audio/mpeg"}},
+		{URL: "mp3-hls", Preset: "mp3_1_0", Quality: "sq", Format: Format{Protocol: "hls", MimeType: "audio/mpeg"}},
+		{URL: "opus-hls", Preset: "opus_0_0", Quality: "sq", Format: Format{Protocol: "hls", MimeType: `audio/ogg; codecs="opus"`}},
+		{URL: "aac-hq", Preset: "aac_256k", Quality: "hq", Format: Format{Protocol: "hls", MimeType: `audio/mp4; codecs="mp4a.40.2"`}},
+		{URL: "aac-sq", Preset: "aac_160k", Quality: "sq", Format: Format{Protocol: "hls", MimeType: `audio/mp4; codecs="mp4a.40.2"`}},
+	}
+
+	tests := []struct {
+		name string
+		opts StreamOptions
+		want string
+	}{
+		{"default prefers progressive", StreamOptions{}, "mp3-prog"},
+		{"format fallback", StreamOptions{Formats: []string{"aac", "mp3"}}, "aac-hq"},
+		{"quality", StreamOptions{Formats: []string{"aac"}, Qualities: []string{"sq"}}, "aac-sq"},
+		{"protocol", StreamOptions{Formats: []string{"mp3"}, Protocols: []string{"hls"}}, "mp3-hls"},
+		{"no match", StreamOptions{Formats: []string{"opus"}, Protocols: []string{"progressive"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectTranscoding(transcodings, tt.opts)
+			var gotURL string
+			if got != nil {
+				gotURL = got.URL
+			}
+			if gotURL != tt.want {
+				t.Fatalf("selectTranscoding = %q, want %q", gotURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStreamOptions(t *testing.T) {
+	opts, err := ParseStreamOptions(" AAC, mp3 ,aac", "", "hq")
+	if err != nil {
+		t.Fatalf("ParseStreamOptions returned error: %v", err)
+	}
+	if len(opts.Formats) != 2 || opts.Formats[0] != "aac" || opts.Formats[1] != "mp3" {
+		t.Fatalf("Formats = %v, want [aac mp3]", opts.Formats)
+	}
+	if len(opts.Protocols) != 0 {
+		t.Fatalf("Protocols = %v, want empty", opts.Protocols)
+	}
+
+	_, err = ParseStreamOptions("flac", "", "")
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "INVALID_STREAM_OPTIONS" {
+		t.Fatalf("expected INVALID_STREAM_OPTIONS, got %v", err)
+	}
+}
+
+func TestGetStreamURL_PassesSecretToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("secret_token"); got != "s-AbCdE" {
+			t.Errorf("resolve secret_token = %q, want s-AbCdE", got)
+		}
+		writeJSON(w, map[string]interface{}{
+			"id":                  int64(1),
+			"kind":                "track",
+			"title":               "Demo",
+			"sharing":             "private",
+			"track_authorization": "auth",
+			"media": map[string]interface{}{
+				"transcodings": []interface{}{
+					map[string]interface{}{
+						"url":    "https://api-v2.soundcloud.com/media/soundcloud:tracks:1/abc/stream/progressive",
+						"format": map[string]interface{}{"protocol": "progressive", "mime_type": "audio/mpeg"},
+					},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/media/soundcloud:tracks:1/abc/stream/progressive", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("secret_token") != "s-AbCdE" || q.Get("track_authorization") != "auth" {
+			t.Errorf("stream query = %v, want secret_token and track_authorization", q)
+		}
+		writeJSON(w, map[string]string{"url": "https://cf-media.sndcdn.com/abc.mp3"})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	result, err := c.GetStreamURL(context.Background(), "https://soundcloud.com/artist/demo/s-AbCdE", StreamOptions{})
+	if err != nil {
+		t.Fatalf("GetStreamURL returned error: %v", err)
+	}
+	if result.Track.SecretToken != "s-AbCdE" || !result.Track.IsPrivate() {
+		t.Fatalf("track = %+v, want private with secret token", result.Track)
+	}
+}
+
+func TestResolvePlaylist_SetSlugIsNotSecretToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("url") != "https://soundcloud.com/artist/sets/s-club" || q.Has("secret_token") {
+			t.Errorf("resolve query = %v, want the full set URL without secret_token", q)
+		}
+		writeJSON(w, map[string]interface{}{"id": int64(7), "kind": "playlist", "title": "S Club"})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	playlist, err := c.ResolvePlaylist(context.Background(), "https://soundcloud.com/artist/sets/s-club")
+	if err != nil {
+		t.Fatalf("ResolvePlaylist returned error: %v", err)
+	}
+	if playlist.SecretToken != "" {
+		t.Fatalf("SecretToken = %q, want empty", playlist.SecretToken)
+	}
+}
@@ -0,0 +1,214 @@
+package scclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const DefaultCredentialCooldown = time.Minute
+
+// Credential is an OAuth token and client_id pair requests can be made with.
+type Credential struct {
+	AuthToken string
+	ClientID  string
+}
+
+// CredentialStatus describes the state of one credential in the pool. Valid
+// and Error reflect the last CheckCredentials call.
+type CredentialStatus struct {
+	Index         int
+	ClientID      string
+	Valid         bool
+	Error         string
+	CoolingDown   bool
+	CooldownUntil time.Time
+	LastStatus    int
+	Requests      int64
+}
+
+// WithCredentials adds credentials to the pool the client spreads requests
+// over, after the one passed to New.
+func WithCredentials(creds ...Credential) Option {
+	return func(s *SoundCloudClient) {
+		for _, c := range creds {
+			s.creds.add(c)
+		}
+	}
+}
+
+// WithCredentialCooldown sets how long a credential is skipped after a 401, 403
+// or 429. A longer Retry-After on a 429 wins. Non-positive values are ignored.
+func WithCredentialCooldown(d time.Duration) Option {
+	return func(s *SoundCloudClient) {
+		if d > 0 {
+			s.creds.cooldown = d
+		}
+	}
+}
+
+type credential struct {
+	index         int
+	authToken     string
+	clientID      string
+	cooldownUntil time.Time
+	lastStatus    int
+	lastError     string
+	valid         bool
+	requests      int64
+}
+
+// credentialPool hands out credentials round-robin, skipping ones that are
+// cooling down. The first credential is the primary one: OAuth refresh and the
+// ClientID/AuthToken accessors operate on it.
+type credentialPool struct {
+	mu       sync.Mutex
+	creds    []*credential
+	next     int
+	cooldown time.Duration
+}
+
+func newCredentialPool(primary Credential) *credentialPool {
+	p := &credentialPool{cooldown: DefaultCredentialCooldown}
+	p.add(primary)
+	return p
+}
+
+func (p *credentialPool) add(c Credential) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.creds = append(p.creds, &credential{
+		index:     len(p.creds),
+		authToken: c.AuthToken,
+		clientID:  c.ClientID,
+		valid:     true,
+	})
+}
+
+// pick returns the next available credential other than exclude. When all
+// are cooling down it returns the one that recovers first, or nil if exclude
+// is set, so callers don't retry with a credential that is known to be bad.
+func (p *credentialPool) pick(exclude *credential) *credential {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	n := len(p.creds)
+	for i := 0; i < n; i++ {
+		c := p.creds[(p.next+i)%n]
+		if c == exclude || now.Before(c.cooldownUntil) {
+			continue
+		}
+		p.next = (c.index + 1) % n
+		return c
+	}
+	if exclude != nil {
+		return nil
+	}
+
+	soonest := p.creds[0]
+	for _, c := range p.creds[1:] {
+		if c.cooldownUntil.Before(soonest.cooldownUntil) {
+			soonest = c
+		}
+	}
+	return soonest
+}
+
+func (p *credentialPool) primary() *credential {
+	return p.creds[0]
+}
+
+func (p *credentialPool) get(c *credential) (authToken, clientID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.requests++
+	return c.authToken, c.clientID
+}
+
+// report records the outcome of a request made with c and puts it on
+// cool-down when upstream rejected it.
+func (p *credentialPool) report(c *credential, resp *http.Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c.lastStatus = resp.StatusCode
+	if !isCredentialFailure(resp.StatusCode) {
+		c.cooldownUntil = time.Time{}
+		return
+	}
+
+	cooldown := p.cooldown
+	if d, ok := retryAfter(resp); ok && resp.StatusCode == http.StatusTooManyRequests && d > cooldown {
+		cooldown = d
+	}
+	c.cooldownUntil = time.Now().Add(cooldown)
+	c.lastError = "status code " + strconv.Itoa(resp.StatusCode)
+}
+
+func isCredentialFailure(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusTooManyRequests
+}
+
+// CheckCredentials validates every credential against /me and returns the
+// status of each.
+func (s *SoundCloudClient) CheckCredentials(ctx context.Context) []CredentialStatus {
+	for _, c := range s.creds.creds {
+		s.checkCredential(ctx, c)
+	}
+	return s.CredentialStatuses()
+}
+
+// CredentialStatuses returns the current state of every credential without
+// making requests.
+func (s *SoundCloudClient) CredentialStatuses() []CredentialStatus {
+	p := s.creds
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]CredentialStatus, 0, len(p.creds))
+	for _, c := range p.creds {
+		statuses = append(statuses, CredentialStatus{
+			Index:         c.index,
+			ClientID:      c.clientID,
+			Valid:         c.valid,
+			Error:         c.lastError,
+			CoolingDown:   now.Before(c.cooldownUntil),
+			CooldownUntil: c.cooldownUntil,
+			LastStatus:    c.lastStatus,
+			Requests:      c.requests,
+		})
+	}
+	return statuses
+}
+
+func (s *SoundCloudClient) checkCredential(ctx context.Context, c *credential) (bool, string) {
+	req, _ := http.NewRequest("GET", s.endpoint("/me"), nil)
+	resp, err := s.sendWithRefresh(ctx, req, c)
+
+	var valid bool
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	} else {
+		defer resp.Body.Close()
+		// Probes leave the cool-down alone: a /me success says nothing
+		// about the Retry-After a real request got.
+		if resp.StatusCode == 200 {
+			valid = true
+		} else {
+			body, _ := io.ReadAll(resp.Body)
+			errMsg = "status code " + strconv.Itoa(resp.StatusCode) + ": " + string(body)
+		}
+	}
+
+	s.creds.mu.Lock()
+	c.valid = valid
+	c.lastError = errMsg
+	s.creds.mu.Unlock()
+	return valid, errMsg
+}
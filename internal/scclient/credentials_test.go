@@ -0,0 +1,144 @@
+package scclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_RotatesCredentialOnRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		seen = append(seen, auth+"/"+r.URL.Query().Get("client_id"))
+		mu.Unlock()
+		if auth == "OAuth first" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": int64(1), "kind": "track", "title": "Song"})
+	}))
+
+	c := New("first", "cid-1", time.Second, WithBaseURL(srv.URL),
+		WithCredentials(Credential{AuthToken: "second", ClientID: "cid-2"}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err != nil {
+			t.Fatalf("ResolveTrack #%d returned error: %v", i, err)
+		}
+	}
+
+	want := []string{"OAuth first/cid-1", "OAuth second/cid-2", "OAuth second/cid-2"}
+	if len(seen) != len(want) {
+		t.Fatalf("requests = %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("requests = %v, want %v", seen, want)
+		}
+	}
+
+	statuses := c.CredentialStatuses()
+	if !statuses[0].CoolingDown || statuses[0].LastStatus != http.StatusTooManyRequests {
+		t.Fatalf("credential 0 = %+v, want cooling down after 429", statuses[0])
+	}
+	if until := time.Until(statuses[0].CooldownUntil); until < 100*time.Second {
+		t.Fatalf("cooldown = %s, want Retry-After to be honoured", until)
+	}
+	if statuses[1].CoolingDown || statuses[1].Requests != 2 {
+		t.Fatalf("credential 1 = %+v, want healthy with 2 requests", statuses[1])
+	}
+}
+
+func TestCredentialPool_PicksRoundRobinAndFallsBack(t *testing.T) {
+	p := newCredentialPool(Credential{AuthToken: "a"})
+	p.add(Credential{AuthToken: "b"})
+	p.add(Credential{AuthToken: "c"})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, p.pick(nil).authToken)
+	}
+	if got[0] != "a" || got[1] != "b" || got[2] != "c" || got[3] != "a" {
+		t.Fatalf("picks = %v, want round-robin", got)
+	}
+
+	now := time.Now()
+	p.creds[0].cooldownUntil = now.Add(time.Minute)
+	p.creds[1].cooldownUntil = now.Add(time.Second)
+	p.creds[2].cooldownUntil = now.Add(time.Hour)
+	if c := p.pick(nil); c.authToken != "b" {
+		t.Fatalf("pick = %q, want the credential that recovers first", c.authToken)
+	}
+	if c := p.pick(p.creds[1]); c != nil {
+		t.Fatalf("pick with exclude = %q, want nil when all are cooling down", c.authToken)
+	}
+}
+
+func TestCheckCredentials_ReportsEachCredential(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": int64(1)})
+	}))
+
+	c := New("good", "cid", time.Second, WithBaseURL(srv.URL),
+		WithCredentials(Credential{AuthToken: "bad", ClientID: "cid"}))
+
+	statuses := c.CheckCredentials(context.Background())
+	if len(statuses) != 2 {
+		t.Fatalf("len(statuses) = %d, want 2", len(statuses))
+	}
+	if !statuses[0].Valid {
+		t.Fatalf("credential 0 = %+v, want valid", statuses[0])
+	}
+	if statuses[1].Valid || statuses[1].Error == "" {
+		t.Fatalf("credential 1 = %+v, want invalid", statuses[1])
+	}
+}
+
+func TestCheckCredentials_KeepsRateLimitCooldown(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/me" {
+			writeJSON(w, map[string]interface{}{"id": int64(1)})
+			return
+		}
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	c := New("only", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err == nil {
+		t.Fatal("ResolveTrack succeeded, want rate limit error")
+	}
+
+	statuses := c.CheckCredentials(context.Background())
+	if !statuses[0].Valid || !statuses[0].CoolingDown {
+		t.Fatalf("credential 0 = %+v, want valid and still cooling down", statuses[0])
+	}
+	if until := time.Until(statuses[0].CooldownUntil); until < 500*time.Second {
+		t.Fatalf("cooldown = %s, want Retry-After to be kept", until)
+	}
+}
+
+func TestSetClientID_UpdatesSharedAndEmptyClientIDs(t *testing.T) {
+	c := New("a", "stale", time.Second,
+		WithCredentials(Credential{AuthToken: "b", ClientID: "stale"}, Credential{AuthToken: "c"}, Credential{AuthToken: "d", ClientID: "own"}))
+
+	c.setClientID("fresh")
+
+	var got []string
+	for _, cs := range c.CredentialStatuses() {
+		got = append(got, cs.ClientID)
+	}
+	if got[0] != "fresh" || got[1] != "fresh" || got[2] != "fresh" || got[3] != "own" {
+		t.Fatalf("client IDs = %v, want [fresh fresh fresh own]", got)
+	}
+}
@@ -0,0 +1,103 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+)
+
+const DefaultWebURL = "https://soundcloud.com"
+
+const (
+	// minRediscoverInterval keeps a burst of 401s from hammering soundcloud.com.
+	minRediscoverInterval = time.Minute
+	maxDiscoveryBodySize  = 8 << 20
+)
+
+var (
+	scriptSrcRe = regexp.MustCompile(`<script[^>]+src="([^"]+\.js)"`)
+	clientIDRe  = regexp.MustCompile(`client_id\s*[:=]\s*"?([A-Za-z0-9]{32})\b`)
+)
+
+// DiscoverClientID scrapes the client_id the soundcloud.com web app currently
+// uses from its JavaScript bundles. It does not change the client's client_id;
+// see RefreshClientID for that.
+func (s *SoundCloudClient) DiscoverClientID(ctx context.Context) (string, error) {
+	pageURL, err := url.Parse(s.webURL + "/")
+	if err != nil {
+		return "", err
+	}
+	page, err := s.fetchWebAsset(ctx, pageURL.String())
+	if err != nil {
+		return "", err
+	}
+
+	var scripts []string
+	for _, m := range scriptSrcRe.FindAllSubmatch(page, -1) {
+		src, err := pageURL.Parse(string(m[1]))
+		if err != nil {
+			continue
+		}
+		scripts = append(scripts, src.String())
+	}
+
+	// The app bundle that carries the client_id is loaded last, so start there.
+	for _, src := range slices.Backward(scripts) {
+		js, err := s.fetchWebAsset(ctx, src)
+		if err != nil {
+			s.logDebug("client_id discovery: skipping %s: %v", src, err)
+			continue
+		}
+		if m := clientIDRe.FindSubmatch(js); m != nil {
+			return string(m[1]), nil
+		}
+	}
+	return "", errors.New("client_id discovery: no client_id found in " + pageURL.String())
+}
+
+// RefreshClientID discovers the current client_id and starts using it. Calls
+// within a minute of a previous successful refresh return the client_id found
+// then without going to the network.
+func (s *SoundCloudClient) RefreshClientID(ctx context.Context) (string, error) {
+	s.discoverMu.Lock()
+	defer s.discoverMu.Unlock()
+
+	if !s.lastDiscovery.IsZero() && time.Since(s.lastDiscovery) < minRediscoverInterval {
+		return s.ClientID(), nil
+	}
+
+	clientID, err := s.DiscoverClientID(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.lastDiscovery = time.Now()
+	if clientID != s.ClientID() {
+		s.setClientID(clientID)
+		s.logInfo("Discovered new SoundCloud client_id")
+	}
+	return clientID, nil
+}
+
+func (s *SoundCloudClient) fetchWebAsset(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, &UpstreamError{Op: "GET " + rawURL, StatusCode: resp.StatusCode}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDiscoveryBodySize))
+}
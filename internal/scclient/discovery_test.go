@@ -0,0 +1,99 @@
+package scclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	staleClientID = "stalestalestalestalestalestale00"
+	freshClientID = "fReSh0123456789abcdefABCDEF01234"
+)
+
+func newWebFixture(t *testing.T) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `<!DOCTYPE html><html><body>
+<script crossorigin src="/assets/vendor-1.js"></script>
+<script crossorigin src="/assets/missing.js"></script>
+<script crossorigin src="/assets/app-2.js"></script>
+</body></html>`)
+	})
+	mux.HandleFunc("/assets/vendor-1.js", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `var x={client_id:"notthisonenotthisonenotthisone00"};`)
+	})
+	mux.HandleFunc("/assets/app-2.js", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `(function(){var e={env:"production",client_id:"`+freshClientID+`",app_version:"1"}})();`)
+	})
+	return newTestServer(t, mux).URL
+}
+
+func TestDiscoverClientID_FromFixture(t *testing.T) {
+	c := New("", "", time.Second, WithWebURL(newWebFixture(t)))
+
+	got, err := c.DiscoverClientID(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverClientID returned error: %v", err)
+	}
+	if got != freshClientID {
+		t.Fatalf("client_id = %q, want %q", got, freshClientID)
+	}
+	if c.ClientID() != "" {
+		t.Fatal("DiscoverClientID must not change the client_id")
+	}
+}
+
+func TestResolve_RediscoversClientIDOnUnauthorized(t *testing.T) {
+	var seen []string
+	api := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID := r.URL.Query().Get("client_id")
+		seen = append(seen, clientID)
+		if clientID != freshClientID {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": 1, "kind": "track", "title": "Song"})
+	}))
+
+	c := New("", staleClientID, time.Second,
+		WithBaseURL(api.URL),
+		WithWebURL(newWebFixture(t)),
+		WithClientIDDiscovery(true),
+	)
+
+	track, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/song")
+	if err != nil {
+		t.Fatalf("ResolveTrack returned error: %v", err)
+	}
+	if track.Title != "Song" {
+		t.Fatalf("unexpected track: %+v", track)
+	}
+	if len(seen) != 2 || seen[0] != staleClientID || seen[1] != freshClientID {
+		t.Fatalf("client_ids sent = %v", seen)
+	}
+	if c.ClientID() != freshClientID {
+		t.Fatalf("ClientID() = %q, want %q", c.ClientID(), freshClientID)
+	}
+}
+
+func TestResolve_NoRediscoveryWhenDisabled(t *testing.T) {
+	api := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	c := New("", staleClientID, time.Second, WithBaseURL(api.URL), WithWebURL(newWebFixture(t)))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/song"); err == nil {
+		t.Fatal("expected error")
+	}
+	if c.ClientID() != staleClientID {
+		t.Fatalf("ClientID() = %q, want unchanged", c.ClientID())
+	}
+}
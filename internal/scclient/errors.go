@@ -0,0 +1,117 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Sentinel errors classifying why a request failed. Errors returned by the
+// client match them with errors.Is; use errors.As with *UpstreamError to get
+// the upstream status code.
+var (
+	// ErrNotFound means the requested resource doesn't exist upstream.
+	ErrNotFound = errors.New("not found")
+	// ErrGeoBlocked means the track is not available in the server's region.
+	ErrGeoBlocked = errors.New("geo blocked")
+	// ErrUnauthorized means upstream rejected the credentials, or they don't
+	// grant access to the resource.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means upstream answered 429.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNoTranscoding means the track has no stream matching the request.
+	ErrNoTranscoding = errors.New("no transcoding")
+	// ErrUpstream means upstream failed or returned an unusable response.
+	ErrUpstream = errors.New("upstream error")
+	// ErrUpstreamUnavailable means the circuit breaker is open.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// Error is returned by the client when a request can't be served. Code is a
+// stable, machine-readable identifier that is passed through to API clients.
+// Err, if set, is the underlying cause and usually matches one of the
+// sentinel errors.
+type Error struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func wrapError(code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// errUpstreamUnavailable is returned without contacting upstream while the
+// circuit breaker is open.
+var errUpstreamUnavailable = wrapError("UPSTREAM_UNAVAILABLE", "SoundCloud is temporarily unavailable, try again later", ErrUpstreamUnavailable)
+
+// UpstreamError reports an unexpected HTTP status from SoundCloud. It matches
+// ErrUpstream, and ErrNotFound, ErrUnauthorized or ErrRateLimited depending on
+// the status.
+type UpstreamError struct {
+	Op         string
+	StatusCode int
+}
+
+func (e *UpstreamError) Error() string {
+	return e.Op + " failed: " + strconv.Itoa(e.StatusCode)
+}
+
+func (e *UpstreamError) Is(target error) bool {
+	switch target {
+	case ErrUpstream:
+		return true
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Classify turns any error returned by the client into an *Error. Errors that
+// already are one are passed through, and rejected credentials, rate limits,
+// timeouts, network errors and other upstream failures get codes of their
+// own. Only missing resources are reported with fallbackCode and
+// fallbackMessage. The result still matches the original error with
+// errors.Is and errors.As.
+func Classify(err error, fallbackCode, fallbackMessage string) *Error {
+	var (
+		scErr  *Error
+		urlErr *url.Error
+	)
+	switch {
+	case errors.As(err, &scErr):
+		return scErr
+	case errors.Is(err, ErrUnauthorized):
+		return wrapError("UNAUTHORIZED", "SoundCloud denied access", err)
+	case errors.Is(err, ErrRateLimited):
+		return wrapError("RATE_LIMITED", "SoundCloud rate limit reached, try again later", err)
+	case errors.Is(err, ErrNotFound):
+		return wrapError(fallbackCode, fallbackMessage, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return wrapError("TIMEOUT", "SoundCloud didn't answer in time", err)
+	case errors.As(err, &urlErr):
+		return wrapError("NETWORK_ERROR", "Network error: "+urlErr.Err.Error(), err)
+	case errors.Is(err, ErrUpstream):
+		return wrapError("UPSTREAM_ERROR", "SoundCloud request failed", err)
+	default:
+		return wrapError("INTERNAL_ERROR", "Internal server error", err)
+	}
+}
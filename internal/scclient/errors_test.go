@@ -0,0 +1,156 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpstreamError_MatchesSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+	}
+	for _, tt := range tests {
+		err := error(&UpstreamError{Op: "resolve", StatusCode: tt.status})
+		if !errors.Is(err, tt.want) || !errors.Is(err, ErrUpstream) {
+			t.Fatalf("status %d: errors.Is(%v) = false, want true", tt.status, tt.want)
+		}
+	}
+	if errors.Is(&UpstreamError{StatusCode: http.StatusBadGateway}, ErrNotFound) {
+		t.Fatal("502 matched ErrNotFound")
+	}
+}
+
+func TestGetStreamURL_ClassifiesResolveFailures(t *testing.T) {
+	tests := []struct {
+		status   int
+		wantCode string
+		wantErr  error
+	}{
+		{http.StatusNotFound, "TRACK_NOT_FOUND", ErrNotFound},
+		{http.StatusUnauthorized, "UNAUTHORIZED", ErrUnauthorized},
+		{http.StatusTooManyRequests, "RATE_LIMITED", ErrRateLimited},
+		{http.StatusInternalServerError, "UPSTREAM_ERROR", ErrUpstream},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+		_, err := c.GetStreamURL(context.Background(), "https://soundcloud.com/a/b", StreamOptions{})
+		var scErr *Error
+		if !errors.As(err, &scErr) || scErr.Code != tt.wantCode {
+			t.Fatalf("status %d: err = %v, want code %s", tt.status, err, tt.wantCode)
+		}
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("status %d: errors.Is(%v) = false", tt.status, tt.wantErr)
+		}
+		var ue *UpstreamError
+		if !errors.As(err, &ue) || ue.StatusCode != tt.status {
+			t.Fatalf("status %d: UpstreamError = %+v", tt.status, ue)
+		}
+	}
+}
+
+func TestClassify_KeepsUpstreamFailuresApartFromNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{"not found", &UpstreamError{Op: "resolve", StatusCode: http.StatusNotFound}, "TRACK_NOT_FOUND"},
+		{"server error", &UpstreamError{Op: "resolve", StatusCode: http.StatusInternalServerError}, "UPSTREAM_ERROR"},
+		{"decode", fmt.Errorf("%w: decoding resolve: %w", ErrUpstream, errors.New("invalid character")), "UPSTREAM_ERROR"},
+		{"network", fmt.Errorf("%w: %w", ErrUpstream, &url.Error{Op: "Get", URL: "https://x", Err: errors.New("connection refused")}), "NETWORK_ERROR"},
+		{"timeout", fmt.Errorf("%w: %w", ErrUpstream, &url.Error{Op: "Get", URL: "https://x", Err: context.DeadlineExceeded}), "TIMEOUT"},
+		{"coded", newError("INVALID_URL", "bad"), "INVALID_URL"},
+	}
+	for _, tt := range tests {
+		got := Classify(tt.err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+		if got.Code != tt.wantCode {
+			t.Fatalf("%s: code = %s, want %s", tt.name, got.Code, tt.wantCode)
+		}
+		if !errors.Is(got, tt.err) {
+			t.Fatalf("%s: result doesn't match the original error", tt.name)
+		}
+	}
+}
+
+func TestGetStreamURLByID_NetworkErrorIsNotNotFound(t *testing.T) {
+	srv := newTestServer(t, http.NotFoundHandler())
+	srv.Close()
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	_, err := c.GetStreamURLByID(context.Background(), 1, StreamOptions{})
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "NETWORK_ERROR" {
+		t.Fatalf("err = %v, want code NETWORK_ERROR", err)
+	}
+}
+
+func TestResolveTrack_DecodeFailureIsUpstreamError(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+
+	_, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b")
+	if !errors.Is(err, ErrUpstream) || errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrUpstream only", err)
+	}
+}
+
+func TestStreamTrack_GeoBlockAndNoTranscodingMatchSentinels(t *testing.T) {
+	c := New("", "cid", time.Second)
+
+	_, err := c.StreamTrack(context.Background(), &Track{Policy: "BLOCK"}, StreamOptions{})
+	if !errors.Is(err, ErrGeoBlocked) {
+		t.Fatalf("err = %v, want ErrGeoBlocked", err)
+	}
+
+	_, err = c.StreamTrack(context.Background(), &Track{}, StreamOptions{})
+	if !errors.Is(err, ErrNoTranscoding) {
+		t.Fatalf("err = %v, want ErrNoTranscoding", err)
+	}
+}
+
+func TestStreamTrack_TimeoutHidesRequestURL(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	c := New("", "secret-client-id", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	track := &Track{
+		TrackAuthorization: "secret-auth",
+		Media: Media{Transcodings: []Transcoding{{
+			URL:    srv.URL + "/media/soundcloud:tracks:1/abc/stream/progressive",
+			Format: Format{Protocol: "progressive", MimeType: "audio/mpeg"},
+		}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.StreamTrack(ctx, track, StreamOptions{})
+
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "TIMEOUT" {
+		t.Fatalf("err = %v, want code TIMEOUT", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want it to match context.DeadlineExceeded", err)
+	}
+	if strings.Contains(scErr.Message, "secret-client-id") || strings.Contains(scErr.Message, "secret-auth") {
+		t.Fatalf("Message = %q leaks the request URL", scErr.Message)
+	}
+}
@@ -0,0 +1,184 @@
+package scclient
+
+// Track is a track object as returned by api-v2.
+type Track struct {
+	ID                 int64              `json:"id"`
+	Kind               string             `json:"kind"`
+	URN                string             `json:"urn"`
+	Title              string             `json:"title"`
+	Description        string             `json:"description"`
+	Duration           int64              `json:"duration"`
+	FullDuration       int64              `json:"full_duration"`
+	Permalink          string             `json:"permalink"`
+	PermalinkURL       string             `json:"permalink_url"`
+	ArtworkURL         string             `json:"artwork_url"`
+	WaveformURL        string             `json:"waveform_url"`
+	Genre              string             `json:"genre"`
+	TagList            string             `json:"tag_list"`
+	LabelName          string             `json:"label_name"`
+	License            string             `json:"license"`
+	ReleaseDate        string             `json:"release_date"`
+	DisplayDate        string             `json:"display_date"`
+	CreatedAt          string             `json:"created_at"`
+	LastModified       string             `json:"last_modified"`
+	PlaybackCount      int64              `json:"playback_count"`
+	LikesCount         int64              `json:"likes_count"`
+	RepostsCount       int64              `json:"reposts_count"`
+	CommentCount       int64              `json:"comment_count"`
+	DownloadCount      int64              `json:"download_count"`
+	Streamable         bool               `json:"streamable"`
+	Downloadable       bool               `json:"downloadable"`
+	Public             bool               `json:"public"`
+	Sharing            string             `json:"sharing"`
+	State              string             `json:"state"`
+	Policy             string             `json:"policy"`
+	MonetizationModel  string             `json:"monetization_model"`
+	SecretToken        string             `json:"secret_token,omitempty"`
+	TrackAuthorization string             `json:"track_authorization,omitempty"`
+	StationURN         string             `json:"station_urn"`
+	StationPermalink   string             `json:"station_permalink"`
+	UserID             int64              `json:"user_id"`
+	User               *User              `json:"user,omitempty"`
+	Media              Media              `json:"media"`
+	PublisherMetadata  *PublisherMetadata `json:"publisher_metadata,omitempty"`
+}
+
+// User is a user (artist) object as returned by api-v2.
+type User struct {
+	ID              int64  `json:"id"`
+	Kind            string `json:"kind"`
+	URN             string `json:"urn"`
+	Username        string `json:"username"`
+	FullName        string `json:"full_name"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Permalink       string `json:"permalink"`
+	PermalinkURL    string `json:"permalink_url"`
+	AvatarURL       string `json:"avatar_url"`
+	City            string `json:"city"`
+	CountryCode     string `json:"country_code"`
+	Description     string `json:"description"`
+	FollowersCount  int64  `json:"followers_count"`
+	FollowingsCount int64  `json:"followings_count"`
+	TrackCount      int64  `json:"track_count"`
+	PlaylistCount   int64  `json:"playlist_count"`
+	LikesCount      int64  `json:"likes_count"`
+	RepostsCount    int64  `json:"reposts_count"`
+	Verified        bool   `json:"verified"`
+	CreatedAt       string `json:"created_at"`
+	LastModified    string `json:"last_modified"`
+}
+
+// Media holds the transcodings a track can be streamed in.
+type Media struct {
+	Transcodings []Transcoding `json:"transcodings"`
+}
+
+// Transcoding is a single encoded rendition of a track. URL points at the
+// api-v2 endpoint that hands out the actual, short-lived stream URL.
+type Transcoding struct {
+	URL      string `json:"url"`
+	Preset   string `json:"preset"`
+	Duration int64  `json:"duration"`
+	Snippet  bool   `json:"snippet"`
+	Format   Format `json:"format"`
+	Quality  string `json:"quality"`
+	IsLegacy bool   `json:"is_legacy_transcoding"`
+}
+
+type Format struct {
+	Protocol string `json:"protocol"`
+	MimeType string `json:"mime_type"`
+}
+
+// PublisherMetadata carries label and rights information for a track.
+type PublisherMetadata struct {
+	ID              int64  `json:"id"`
+	URN             string `json:"urn"`
+	Artist          string `json:"artist"`
+	AlbumTitle      string `json:"album_title"`
+	ContainsMusic   bool   `json:"contains_music"`
+	UPCOrEAN        string `json:"upc_or_ean"`
+	ISRC            string `json:"isrc"`
+	Explicit        bool   `json:"explicit"`
+	PLine           string `json:"p_line"`
+	PLineForDisplay string `json:"p_line_for_display"`
+	CLine           string `json:"c_line"`
+	CLineForDisplay string `json:"c_line_for_display"`
+	WriterComposer  string `json:"writer_composer"`
+	ReleaseTitle    string `json:"release_title"`
+	Publisher       string `json:"publisher"`
+}
+
+// StreamResult is a resolved stream URL together with the track it belongs to.
+// URL is a direct file for progressive transcodings and an m3u8 playlist for
+// HLS ones; Transcoding tells which.
+type StreamResult struct {
+	URL         string
+	Track       *Track
+	Transcoding *Transcoding
+}
+
+// Playlist is a set or album as returned by api-v2. Tracks beyond the first
+// few come back as stubs that only carry an ID; ResolvePlaylist fills them in.
+type Playlist struct {
+	ID           int64   `json:"id"`
+	Kind         string  `json:"kind"`
+	URN          string  `json:"urn"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	Duration     int64   `json:"duration"`
+	Permalink    string  `json:"permalink"`
+	PermalinkURL string  `json:"permalink_url"`
+	ArtworkURL   string  `json:"artwork_url"`
+	Genre        string  `json:"genre"`
+	TagList      string  `json:"tag_list"`
+	LabelName    string  `json:"label_name"`
+	License      string  `json:"license"`
+	ReleaseDate  string  `json:"release_date"`
+	DisplayDate  string  `json:"display_date"`
+	CreatedAt    string  `json:"created_at"`
+	LastModified string  `json:"last_modified"`
+	SetType      string  `json:"set_type"`
+	IsAlbum      bool    `json:"is_album"`
+	Public       bool    `json:"public"`
+	Sharing      string  `json:"sharing"`
+	SecretToken  string  `json:"secret_token,omitempty"`
+	LikesCount   int64   `json:"likes_count"`
+	RepostsCount int64   `json:"reposts_count"`
+	TrackCount   int     `json:"track_count"`
+	UserID       int64   `json:"user_id"`
+	User         *User   `json:"user,omitempty"`
+	Tracks       []Track `json:"tracks"`
+}
+
+// Like is an entry in a user's likes. Exactly one of Track and Playlist is set.
+type Like struct {
+	CreatedAt string    `json:"created_at"`
+	Kind      string    `json:"kind"`
+	Track     *Track    `json:"track,omitempty"`
+	Playlist  *Playlist `json:"playlist,omitempty"`
+}
+
+// Repost is an entry in a user's reposts. Type is "track-repost" or
+// "playlist-repost" and tells which of Track and Playlist is set.
+type Repost struct {
+	CreatedAt string    `json:"created_at"`
+	Type      string    `json:"type"`
+	User      *User     `json:"user,omitempty"`
+	Track     *Track    `json:"track,omitempty"`
+	Playlist  *Playlist `json:"playlist,omitempty"`
+}
+
+// Comment is a comment on a track. Timestamp is the position on the waveform
+// in milliseconds and is nil for comments that aren't attached to one.
+type Comment struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"`
+	Body      string `json:"body"`
+	Timestamp *int64 `json:"timestamp"`
+	CreatedAt string `json:"created_at"`
+	TrackID   int64  `json:"track_id"`
+	UserID    int64  `json:"user_id"`
+	User      *User  `json:"user,omitempty"`
+}
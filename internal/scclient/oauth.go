@@ -0,0 +1,281 @@
+package scclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultTokenURL = "https://secure.soundcloud.com/oauth/token"
+
+const (
+	// tokenRefreshMargin is how long before expiry the access token is renewed.
+	tokenRefreshMargin = 5 * time.Minute
+	// tokenRetryInterval is the wait after a failed background refresh.
+	tokenRetryInterval = 30 * time.Second
+)
+
+// OAuthConfig enables access token renewal through the refresh_token grant.
+// When TokenFile is set, tokens are persisted there and a token found in it
+// at startup takes precedence over AccessToken and RefreshToken, since
+// SoundCloud rotates refresh tokens on every use.
+type OAuthConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	TokenFile    string
+}
+
+// Token is an OAuth token pair as stored in OAuthConfig.TokenFile.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// WithOAuth makes the client renew its access token before it expires and
+// after a 401. Call RunTokenRefresh to enable the background renewal.
+func WithOAuth(cfg OAuthConfig) Option {
+	return func(s *SoundCloudClient) {
+		if cfg.TokenURL == "" {
+			cfg.TokenURL = DefaultTokenURL
+		}
+		s.oauth = &oauthRefresher{cfg: cfg}
+	}
+}
+
+type oauthRefresher struct {
+	cfg    OAuthConfig
+	client *SoundCloudClient
+
+	mu           sync.Mutex
+	refreshToken string
+	expiresAt    time.Time
+	lastRefresh  time.Time
+	// refreshed is closed and replaced after every refresh attempt so the
+	// background loop can re-plan.
+	refreshed chan struct{}
+}
+
+func (o *oauthRefresher) init(s *SoundCloudClient) {
+	o.client = s
+	o.refreshToken = o.cfg.RefreshToken
+	o.refreshed = make(chan struct{})
+
+	if o.cfg.TokenFile == "" {
+		return
+	}
+	tok, err := loadToken(o.cfg.TokenFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			s.logInfo("Ignoring OAuth token file %s: %v", o.cfg.TokenFile, err)
+		}
+		return
+	}
+	if tok.RefreshToken != "" {
+		o.refreshToken = tok.RefreshToken
+	}
+	if tok.AccessToken != "" {
+		s.SetAuthToken(tok.AccessToken)
+		o.expiresAt = tok.ExpiresAt
+	}
+}
+
+// RefreshAuthToken obtains a new access token from the token endpoint and
+// swaps it into the client. It fails if the client was built without WithOAuth.
+func (s *SoundCloudClient) RefreshAuthToken(ctx context.Context) error {
+	if s.oauth == nil {
+		return errors.New("oauth: refresh not configured")
+	}
+	s.oauth.mu.Lock()
+	defer s.oauth.mu.Unlock()
+	return s.oauth.refreshLocked(ctx)
+}
+
+// TokenExpiry returns when the current access token expires, or the zero time
+// when that is unknown.
+func (s *SoundCloudClient) TokenExpiry() time.Time {
+	if s.oauth == nil {
+		return time.Time{}
+	}
+	s.oauth.mu.Lock()
+	defer s.oauth.mu.Unlock()
+	return s.oauth.expiresAt
+}
+
+// RunTokenRefresh renews the access token shortly before it expires until ctx
+// is done. A token of unknown age is renewed right away only if the rotated
+// refresh token can be saved to TokenFile; otherwise, and if the token
+// endpoint doesn't report an expiry either, renewal only happens after a 401.
+// It is a no-op for clients built without WithOAuth or without a refresh
+// token.
+func (s *SoundCloudClient) RunTokenRefresh(ctx context.Context) {
+	o := s.oauth
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	canRefresh := o.refreshToken != ""
+	o.mu.Unlock()
+	if !canRefresh {
+		s.logInfo("No OAuth refresh token, access token won't be renewed")
+		return
+	}
+
+	for {
+		o.mu.Lock()
+		var due <-chan time.Time
+		switch {
+		case s.AuthToken() == "":
+			due = time.After(0)
+		case o.expiresAt.IsZero() && o.lastRefresh.IsZero() && o.cfg.TokenFile != "":
+			// Without TokenFile, refreshing now would rotate away the
+			// configured refresh token and leave the next start without a
+			// usable one.
+			due = time.After(0)
+		case !o.expiresAt.IsZero():
+			due = time.After(max(time.Until(o.expiresAt.Add(-tokenRefreshMargin)), 0))
+		}
+		refreshed := o.refreshed
+		o.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-refreshed:
+			// Someone else refreshed (e.g. after a 401); re-plan.
+			continue
+		case <-due:
+		}
+
+		if err := s.RefreshAuthToken(ctx); err != nil {
+			s.logInfo("OAuth token refresh failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(tokenRetryInterval):
+			}
+		}
+	}
+}
+
+// refreshAfterUnauthorized renews the token after a request made with
+// usedToken got a 401, unless another request already did so.
+func (o *oauthRefresher) refreshAfterUnauthorized(ctx context.Context, usedToken string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.client.AuthToken() != usedToken {
+		return nil
+	}
+	return o.refreshLocked(ctx)
+}
+
+func (o *oauthRefresher) refreshLocked(ctx context.Context) error {
+	defer func() {
+		close(o.refreshed)
+		o.refreshed = make(chan struct{})
+	}()
+
+	if o.refreshToken == "" {
+		return errors.New("oauth: no refresh token")
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {o.cfg.ClientID},
+		"client_secret": {o.cfg.ClientSecret},
+		"refresh_token": {o.refreshToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", o.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", o.client.userAgent)
+
+	resp, err := o.client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return &UpstreamError{Op: "oauth token", StatusCode: resp.StatusCode}
+	}
+
+	var tr struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return err
+	}
+	if tr.AccessToken == "" {
+		return errors.New("oauth: token response without access_token")
+	}
+
+	o.client.SetAuthToken(tr.AccessToken)
+	o.lastRefresh = time.Now()
+	if tr.RefreshToken != "" {
+		o.refreshToken = tr.RefreshToken
+	}
+	o.expiresAt = time.Time{}
+	if tr.ExpiresIn > 0 {
+		o.expiresAt = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	o.client.logInfo("OAuth access token refreshed, expires at %s", o.expiresAt.Format(time.RFC3339))
+
+	if o.cfg.TokenFile != "" {
+		tok := Token{AccessToken: tr.AccessToken, RefreshToken: o.refreshToken, ExpiresAt: o.expiresAt}
+		if err := saveToken(o.cfg.TokenFile, &tok); err != nil {
+			o.client.logInfo("Failed to save OAuth token to %s: %v", o.cfg.TokenFile, err)
+		}
+	}
+	return nil
+}
+
+func loadToken(path string) (*Token, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tok Token
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return nil, err
+	}
+	return &tok, nil
+}
+
+// saveToken writes the token atomically so a crash never leaves a truncated
+// file behind.
+func saveToken(path string, tok *Token) error {
+	b, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,167 @@
+package scclient
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTokenServer(t *testing.T, refreshes *int) string {
+	t.Helper()
+	return newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		if r.Method != http.MethodPost || r.PostForm.Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected token request: %s %v", r.Method, r.PostForm)
+		}
+		if r.PostForm.Get("client_secret") != "secret" || r.PostForm.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*refreshes++
+		writeJSON(w, map[string]interface{}{
+			"access_token":  "access-2",
+			"refresh_token": "refresh-2",
+			"expires_in":    3600,
+		})
+	})).URL
+}
+
+func TestDoRequest_RefreshesTokenAfterUnauthorized(t *testing.T) {
+	var refreshes int
+	tokenURL := newTokenServer(t, &refreshes)
+	api := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "OAuth access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+
+	c := New("access-1", "cid", time.Second,
+		WithBaseURL(api.URL),
+		WithOAuth(OAuthConfig{
+			TokenURL:     tokenURL,
+			ClientID:     "cid",
+			ClientSecret: "secret",
+			RefreshToken: "refresh-1",
+			TokenFile:    tokenFile,
+		}),
+	)
+
+	valid, msg := c.ValidateToken(context.Background())
+	if !valid {
+		t.Fatalf("ValidateToken = false (%s), want true after refresh", msg)
+	}
+	if refreshes != 1 {
+		t.Fatalf("refreshes = %d, want 1", refreshes)
+	}
+	if c.AuthToken() != "access-2" {
+		t.Fatalf("AuthToken() = %q, want %q", c.AuthToken(), "access-2")
+	}
+	if until := time.Until(c.TokenExpiry()); until < 59*time.Minute || until > time.Hour {
+		t.Fatalf("TokenExpiry() in %s, want about 1h", until)
+	}
+
+	saved, err := loadToken(tokenFile)
+	if err != nil {
+		t.Fatalf("loadToken: %v", err)
+	}
+	if saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-2" {
+		t.Fatalf("saved token = %+v", saved)
+	}
+	if info, err := os.Stat(tokenFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("token file mode = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestWithOAuth_LoadsTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := saveToken(tokenFile, &Token{AccessToken: "from-file", RefreshToken: "r", ExpiresAt: expires}); err != nil {
+		t.Fatalf("saveToken: %v", err)
+	}
+
+	c := New("from-env", "cid", time.Second, WithOAuth(OAuthConfig{RefreshToken: "env-refresh", TokenFile: tokenFile}))
+
+	if c.AuthToken() != "from-file" {
+		t.Fatalf("AuthToken() = %q, want %q", c.AuthToken(), "from-file")
+	}
+	if !c.TokenExpiry().Equal(expires) {
+		t.Fatalf("TokenExpiry() = %s, want %s", c.TokenExpiry(), expires)
+	}
+	if c.oauth.refreshToken != "r" {
+		t.Fatalf("refresh token = %q, want %q", c.oauth.refreshToken, "r")
+	}
+}
+
+func TestRunTokenRefresh_RenewsTokenOfUnknownAge(t *testing.T) {
+	var refreshes int
+	tokenURL := newTokenServer(t, &refreshes)
+
+	c := New("access-1", "cid", time.Second, WithOAuth(OAuthConfig{
+		TokenURL:     tokenURL,
+		ClientSecret: "secret",
+		RefreshToken: "refresh-1",
+		TokenFile:    filepath.Join(t.TempDir(), "token.json"),
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.RunTokenRefresh(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.AuthToken() != "access-2" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if c.AuthToken() != "access-2" {
+		t.Fatalf("AuthToken() = %q, want %q", c.AuthToken(), "access-2")
+	}
+}
+
+func TestRunTokenRefresh_KeepsTokenOfUnknownAgeWithoutTokenFile(t *testing.T) {
+	var refreshes int
+	tokenURL := newTokenServer(t, &refreshes)
+
+	c := New("access-1", "cid", time.Second, WithOAuth(OAuthConfig{
+		TokenURL:     tokenURL,
+		ClientSecret: "secret",
+		RefreshToken: "refresh-1",
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	c.RunTokenRefresh(ctx)
+
+	if refreshes != 0 || c.AuthToken() != "access-1" {
+		t.Fatalf("refreshes = %d, AuthToken() = %q, want no refresh", refreshes, c.AuthToken())
+	}
+}
+
+func TestRunTokenRefresh_ReturnsWithoutRefreshToken(t *testing.T) {
+	c := New("access-1", "cid", time.Second, WithOAuth(OAuthConfig{
+		TokenFile: filepath.Join(t.TempDir(), "token.json"),
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		c.RunTokenRefresh(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunTokenRefresh kept running without a refresh token")
+	}
+}
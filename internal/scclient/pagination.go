@@ -0,0 +1,144 @@
+package scclient
+
+import (
+	"context"
+	"encoding/base64"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 200
+)
+
+// Page is one page of an api-v2 collection. NextHref is empty on the last page.
+// TotalResults is only reported by search endpoints.
+type Page[T any] struct {
+	Collection   []T    `json:"collection"`
+	NextHref     string `json:"next_href"`
+	TotalResults int64  `json:"total_results"`
+}
+
+// PageOptions selects a page of a collection. Cursor is a token previously
+// returned by Page.NextCursor and takes precedence over Offset; both empty
+// means the first page. Numeric offsets are only honoured by search.
+type PageOptions struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// NextCursor returns an opaque token for the following page, or "" when there
+// is none. It only carries the offset from next_href, so no upstream host or
+// credentials leak to API clients.
+func (p *Page[T]) NextCursor() string {
+	if p.NextHref == "" {
+		return ""
+	}
+	u, err := url.Parse(p.NextHref)
+	if err != nil {
+		return ""
+	}
+	offset := u.Query().Get("offset")
+	if offset == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(offset))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) == 0 {
+		return "", newError("INVALID_CURSOR", "Invalid pagination cursor")
+	}
+	return string(b), nil
+}
+
+// getPage fetches a single page of the collection at path using
+// linked_partitioning.
+func getPage[T any](ctx context.Context, s *SoundCloudClient, path string, query url.Values, opts PageOptions) (*Page[T], error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("linked_partitioning", "1")
+	if opts.Cursor != "" {
+		offset, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("offset", offset)
+	} else if opts.Offset > 0 {
+		q.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	var page Page[T]
+	if err := s.getJSON(ctx, path, q, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// IterOptions controls Paginate. PageSize is the limit sent with each request
+// and MaxItems stops the iteration after that many items; zero means the
+// default page size and no cap respectively.
+type IterOptions struct {
+	PageSize int
+	MaxItems int
+}
+
+// Paginate walks the collection at path, following next_href until the last
+// page, MaxItems is reached, the consumer stops or ctx is done. Failures are
+// yielded once as the error value, after which the sequence ends.
+func Paginate[T any](ctx context.Context, s *SoundCloudClient, path string, query url.Values, opts IterOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		seen := 0
+
+		page, err := getPage[T](ctx, s, path, query, PageOptions{Limit: opts.PageSize})
+		for {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Collection {
+				if !yield(item, nil) {
+					return
+				}
+				seen++
+				if opts.MaxItems > 0 && seen >= opts.MaxItems {
+					return
+				}
+			}
+			if page.NextHref == "" || len(page.Collection) == 0 {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			page, err = getNextPage[T](ctx, s, page.NextHref)
+		}
+	}
+}
+
+func getNextPage[T any](ctx context.Context, s *SoundCloudClient, nextHref string) (*Page[T], error) {
+	u, err := url.Parse(nextHref)
+	if err != nil {
+		return nil, err
+	}
+	var page Page[T]
+	if err := s.getURL(ctx, u, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
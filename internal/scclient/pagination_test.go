@@ -0,0 +1,169 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetUserTracks_CursorRoundTrip(t *testing.T) {
+	const offset = "2024-01-01T00:00:00.000Z,tracks,00123"
+	var gotOffset, gotLimit, gotPartitioning string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/5/tracks" {
+			t.Errorf("path = %q, want /users/5/tracks", r.URL.Path)
+		}
+		q := r.URL.Query()
+		gotOffset, gotLimit, gotPartitioning = q.Get("offset"), q.Get("limit"), q.Get("linked_partitioning")
+		next := ""
+		if gotOffset == "" {
+			next = "https://api-v2.soundcloud.com/users/5/tracks?offset=2024-01-01T00%3A00%3A00.000Z%2Ctracks%2C00123&limit=2"
+		}
+		writeJSON(w, map[string]interface{}{
+			"collection": []interface{}{map[string]interface{}{"id": int64(1), "title": "One"}},
+			"next_href":  next,
+		})
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	page, err := c.GetUserTracks(context.Background(), 5, PageOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetUserTracks returned error: %v", err)
+	}
+	if gotLimit != "2" || gotPartitioning != "1" {
+		t.Fatalf("limit = %q, linked_partitioning = %q", gotLimit, gotPartitioning)
+	}
+	if len(page.Collection) != 1 || page.Collection[0].Title != "One" {
+		t.Fatalf("unexpected collection: %+v", page.Collection)
+	}
+
+	cursor := page.NextCursor()
+	if cursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	page, err = c.GetUserTracks(context.Background(), 5, PageOptions{Cursor: cursor})
+	if err != nil {
+		t.Fatalf("GetUserTracks returned error: %v", err)
+	}
+	if gotOffset != offset {
+		t.Fatalf("offset = %q, want %q", gotOffset, offset)
+	}
+	if page.NextCursor() != "" {
+		t.Fatal("expected no cursor on last page")
+	}
+}
+
+func TestGetUserTracks_RejectsInvalidCursor(t *testing.T) {
+	c := New("", "cid", time.Second, WithBaseURL("http://127.0.0.1:0"))
+	_, err := c.GetUserTracks(context.Background(), 5, PageOptions{Cursor: "!!!"})
+
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "INVALID_CURSOR" {
+		t.Fatalf("expected INVALID_CURSOR, got %v", err)
+	}
+}
+
+func TestSearch_UsesTypedCollectionAndOffset(t *testing.T) {
+	var gotPath, gotQuery, gotOffset string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("q")
+		gotOffset = r.URL.Query().Get("offset")
+		writeJSON(w, map[string]interface{}{
+			"collection":    []interface{}{map[string]interface{}{"id": int64(9), "kind": "user", "username": "dj"}},
+			"total_results": 31,
+			"next_href":     "https://api-v2.soundcloud.com/search/users?q=dj&offset=20&limit=10",
+		})
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	result, err := c.Search(context.Background(), "dj", "users", PageOptions{Limit: 10, Offset: 10})
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if gotPath != "/search/users" || gotQuery != "dj" || gotOffset != "10" {
+		t.Fatalf("path = %q, q = %q, offset = %q", gotPath, gotQuery, gotOffset)
+	}
+	if len(result.Users) != 1 || result.Users[0].Username != "dj" || result.TotalResults != 31 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.NextCursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	if _, err := c.Search(context.Background(), "dj", "albums", PageOptions{}); err == nil {
+		t.Fatal("expected error for unknown search type")
+	}
+}
+
+func TestPaginate_FollowsNextHrefAndCapsItems(t *testing.T) {
+	var requests int
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("client_id") != "cid" {
+			t.Errorf("client_id missing on %s", r.URL)
+		}
+		switch r.URL.Query().Get("offset") {
+		case "":
+			writeJSON(w, map[string]interface{}{
+				"collection": []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}},
+				"next_href":  srvURL + "/users/5/tracks?offset=b&limit=2",
+			})
+		case "b":
+			writeJSON(w, map[string]interface{}{
+				"collection": []interface{}{map[string]interface{}{"id": 3}, map[string]interface{}{"id": 4}},
+				"next_href":  srvURL + "/users/5/tracks?offset=c&limit=2",
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	srvURL = srv.URL
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	var ids []int64
+	for track, err := range c.UserTracks(context.Background(), 5, IterOptions{PageSize: 2, MaxItems: 3}) {
+		if err != nil {
+			t.Fatalf("iteration error: %v", err)
+		}
+		ids = append(ids, track.ID)
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Fatalf("ids = %v, want [1 2 3]", ids)
+	}
+	if requests != 2 {
+		t.Fatalf("requests = %d, want 2", requests)
+	}
+}
+
+func TestPaginate_StopsOnCancelledContext(t *testing.T) {
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"collection": []interface{}{map[string]interface{}{"id": 1}},
+			"next_href":  srvURL + "/users/5/tracks?offset=next",
+		})
+	}))
+	srvURL = srv.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+
+	var gotErr error
+	for _, err := range c.UserTracks(ctx, 5, IterOptions{}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		cancel()
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", gotErr)
+	}
+}
@@ -0,0 +1,54 @@
+package scclient
+
+import (
+	"context"
+)
+
+// ResolvePlaylist resolves a set URL and fills in the stub tracks api-v2 returns
+// without metadata. Tracks that can't be fetched stay in place as stubs (with
+// an empty Title) so positions are preserved.
+func (s *SoundCloudClient) ResolvePlaylist(ctx context.Context, playlistURL string) (*Playlist, error) {
+	var playlist Playlist
+	if err := s.resolve(ctx, playlistURL, &playlist); err != nil {
+		return nil, err
+	}
+	if playlist.Kind != "playlist" {
+		return nil, newError("NOT_A_PLAYLIST", "URL points to a "+playlist.Kind+", not a playlist")
+	}
+	if _, token := SplitSecretToken(playlistURL); token != "" && playlist.SecretToken == "" {
+		playlist.SecretToken = token
+	}
+
+	var stubIDs []int64
+	for _, t := range playlist.Tracks {
+		if IsStub(&t) {
+			stubIDs = append(stubIDs, t.ID)
+		}
+	}
+	if len(stubIDs) == 0 {
+		return &playlist, nil
+	}
+
+	// The playlist itself resolved, so tracks of a failed batch are left as
+	// stubs rather than failing the whole request. Timeouts, an open breaker
+	// and failures of every batch still fail it.
+	fetched, _, err := s.getTracks(ctx, stubIDs, &playlist, true)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]Track, len(fetched))
+	for _, t := range fetched {
+		byID[t.ID] = t
+	}
+	for i, t := range playlist.Tracks {
+		if full, ok := byID[t.ID]; ok && IsStub(&t) {
+			playlist.Tracks[i] = full
+		}
+	}
+	return &playlist, nil
+}
+
+// IsStub reports whether t is a placeholder that only carries an ID.
+func IsStub(t *Track) bool {
+	return t.Title == "" && t.User == nil
+}
@@ -0,0 +1,133 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestResolvePlaylist_FillsStubTracksInOrder(t *testing.T) {
+	var gotIDs string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"id":    int64(7),
+			"kind":  "playlist",
+			"title": "Set",
+			"tracks": []interface{}{
+				map[string]interface{}{"id": int64(1), "kind": "track", "title": "One", "user": map[string]interface{}{"username": "a"}},
+				map[string]interface{}{"id": int64(2), "kind": "track"},
+				map[string]interface{}{"id": int64(3), "kind": "track"},
+			},
+		})
+	})
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		gotIDs = r.URL.Query().Get("ids")
+		writeJSON(w, []interface{}{
+			map[string]interface{}{"id": int64(3), "kind": "track", "title": "Three", "user": map[string]interface{}{"username": "c"}},
+		})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	playlist, err := c.ResolvePlaylist(context.Background(), "https://soundcloud.com/a/sets/set")
+	if err != nil {
+		t.Fatalf("ResolvePlaylist returned error: %v", err)
+	}
+
+	if gotIDs != "2,3" {
+		t.Fatalf("ids = %q, want %q", gotIDs, "2,3")
+	}
+	if len(playlist.Tracks) != 3 {
+		t.Fatalf("len(Tracks) = %d, want 3", len(playlist.Tracks))
+	}
+	if playlist.Tracks[0].Title != "One" || playlist.Tracks[2].Title != "Three" {
+		t.Fatalf("tracks out of order: %+v", playlist.Tracks)
+	}
+	if !IsStub(&playlist.Tracks[1]) {
+		t.Fatal("missing track should remain a stub")
+	}
+}
+
+func TestResolvePlaylist_KeepsStubsOfFailedBatch(t *testing.T) {
+	stubs := make([]interface{}, tracksBatchSize+1)
+	for i := range stubs {
+		stubs[i] = map[string]interface{}{"id": int64(i + 1), "kind": "track"}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"id": int64(7), "kind": "playlist", "title": "Set", "tracks": stubs})
+	})
+	mux.HandleFunc("/tracks", func(w http.ResponseWriter, r *http.Request) {
+		// The second batch only holds the last track.
+		if ids := r.URL.Query().Get("ids"); ids != strconv.Itoa(tracksBatchSize+1) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, []interface{}{
+			map[string]interface{}{"id": int64(tracksBatchSize + 1), "kind": "track", "title": "Last", "user": map[string]interface{}{"username": "a"}},
+		})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	playlist, err := c.ResolvePlaylist(context.Background(), "https://soundcloud.com/a/sets/set")
+	if err != nil {
+		t.Fatalf("ResolvePlaylist returned error: %v", err)
+	}
+	if len(playlist.Tracks) != tracksBatchSize+1 {
+		t.Fatalf("len(Tracks) = %d, want %d", len(playlist.Tracks), tracksBatchSize+1)
+	}
+	if !IsStub(&playlist.Tracks[0]) || playlist.Tracks[0].ID != 1 {
+		t.Fatalf("track of failed batch = %+v, want stub 1", playlist.Tracks[0])
+	}
+	if last := playlist.Tracks[tracksBatchSize]; last.Title != "Last" {
+		t.Fatalf("last track = %+v, want it filled in", last)
+	}
+}
+
+func TestResolvePlaylist_FailsWhenStubTracksCantBeLoaded(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracks  http.HandlerFunc
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name:    "every batch failed",
+			tracks:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			timeout: time.Second,
+			wantErr: ErrUpstream,
+		},
+		{
+			name:    "timed out",
+			tracks:  func(w http.ResponseWriter, r *http.Request) { <-r.Context().Done() },
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, map[string]interface{}{
+					"id":     int64(7),
+					"kind":   "playlist",
+					"tracks": []interface{}{map[string]interface{}{"id": int64(1), "kind": "track"}},
+				})
+			})
+			mux.HandleFunc("/tracks", tt.tracks)
+			srv := newTestServer(t, mux)
+
+			c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			if _, err := c.ResolvePlaylist(ctx, "https://soundcloud.com/a/sets/set"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how GET requests are retried after connection errors,
+// 429s and 5xx responses. MaxAttempts includes the first attempt, so values
+// below 2 disable retries. Waits grow exponentially from BaseDelay up to
+// MaxDelay with jitter, unless upstream sends Retry-After. No retry is made
+// that would wait past the request context's deadline.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *SoundCloudClient) {
+		s.retry = p
+	}
+}
+
+func (s *SoundCloudClient) doWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	p := s.retry
+	for n := 1; ; n++ {
+		resp, err := s.attempt(ctx, req)
+		if n >= p.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			if n > 1 {
+				s.logDebug("GET %s finished after %d attempts: %s", req.URL.Path, n, attemptResult(resp, err))
+			}
+			return resp, err
+		}
+
+		delay := p.backoff(n)
+		if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+			if d, ok := retryAfter(resp); ok {
+				delay = d
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			s.logDebug("GET %s not retried, %s wait exceeds the deadline: %s", req.URL.Path, delay, attemptResult(resp, err))
+			return resp, err
+		}
+
+		s.logDebug("GET %s attempt %d/%d failed (%s), retrying in %s", req.URL.Path, n, p.MaxAttempts, attemptResult(resp, err), delay)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns the jittered wait before retry n (1-based): a random
+// duration between half and all of BaseDelay*2^(n-1), capped at MaxDelay.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay << (n - 1)
+	if d < p.BaseDelay || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// Don't retry once the caller has given up, nor errors of our own
+		// such as an open circuit breaker.
+		var scErr *Error
+		return ctx.Err() == nil && !errors.As(err, &scErr)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func attemptResult(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "status " + strconv.Itoa(resp.StatusCode)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
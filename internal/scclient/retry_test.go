@@ -0,0 +1,114 @@
+package scclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestDoRequest_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"id": int64(1), "kind": "track", "title": "Song"})
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err != nil {
+		t.Fatalf("ResolveTrack returned error: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestDoRequest_StopsAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestDoRequest_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+	if _, err := c.ResolveTrack(context.Background(), "https://soundcloud.com/a/b"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
+
+func TestDoRequest_RetryAfterBeyondDeadlineIsNotWaitedFor(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.ResolveTrack(ctx, "https://soundcloud.com/a/b"); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("took %s, want no wait for Retry-After past the deadline", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n, want := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(n); d < want/2 || d > want {
+				t.Fatalf("backoff(%d) = %s, want in [%s, %s]", n, d, want/2, want)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if _, ok := retryAfter(resp); ok {
+		t.Fatal("retryAfter without header = ok, want !ok")
+	}
+
+	resp.Header.Set("Retry-After", "7")
+	if d, ok := retryAfter(resp); !ok || d != 7*time.Second {
+		t.Fatalf("retryAfter = %s, %v, want 7s", d, ok)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if d, ok := retryAfter(resp); !ok || d < 58*time.Second || d > time.Minute {
+		t.Fatalf("retryAfter = %s, %v, want about a minute", d, ok)
+	}
+}
@@ -0,0 +1,46 @@
+package scclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// SearchResult holds one page of search results. Only the slice matching the
+// searched type is populated.
+type SearchResult struct {
+	Tracks       []Track
+	Users        []User
+	Playlists    []Playlist
+	TotalResults int64
+	NextCursor   string
+}
+
+// Search runs a query against one of the api-v2 search collections. searchType
+// is one of "tracks", "users" or "playlists".
+func (s *SoundCloudClient) Search(ctx context.Context, query, searchType string, opts PageOptions) (*SearchResult, error) {
+	q := url.Values{"q": {query}}
+	path := "/search/" + searchType
+
+	switch searchType {
+	case "tracks":
+		page, err := getPage[Track](ctx, s, path, q, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResult{Tracks: page.Collection, TotalResults: page.TotalResults, NextCursor: page.NextCursor()}, nil
+	case "users":
+		page, err := getPage[User](ctx, s, path, q, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResult{Users: page.Collection, TotalResults: page.TotalResults, NextCursor: page.NextCursor()}, nil
+	case "playlists":
+		page, err := getPage[Playlist](ctx, s, path, q, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResult{Playlists: page.Collection, TotalResults: page.TotalResults, NextCursor: page.NextCursor()}, nil
+	default:
+		return nil, newError("INVALID_SEARCH_TYPE", "Search type must be one of tracks, users, playlists")
+	}
+}
@@ -0,0 +1,171 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// tracksBatchSize is the maximum number of IDs api-v2 accepts per
+	// /tracks call.
+	tracksBatchSize = 50
+	// tracksConcurrency bounds how many /tracks batches are fetched at once.
+	tracksConcurrency = 4
+)
+
+// trackURNPrefix prefixes track IDs in URNs, e.g. soundcloud:tracks:123.
+const trackURNPrefix = "soundcloud:tracks:"
+
+// ParseTrackID parses a track ID given either as a number or as a URN.
+func ParseTrackID(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, trackURNPrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, newError("INVALID_TRACK_ID", "Track ID must be a number or a soundcloud:tracks:<id> URN")
+	}
+	return id, nil
+}
+
+// GetTrackByID fetches a track by ID without going through /resolve.
+func (s *SoundCloudClient) GetTrackByID(ctx context.Context, id int64) (*Track, error) {
+	var track Track
+	if err := s.getJSON(ctx, "/tracks/"+strconv.FormatInt(id, 10), nil, &track); err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// GetTracks fetches many tracks by ID with as few requests as possible. The
+// tracks are returned in the order of ids; IDs api-v2 returned nothing for
+// (deleted, private or geo-blocked tracks) are listed in missing instead.
+func (s *SoundCloudClient) GetTracks(ctx context.Context, ids []int64) (tracks []Track, missing []int64, err error) {
+	return s.getTracks(ctx, ids, nil, false)
+}
+
+// getTracks is GetTracks for tracks that may belong to playlist. When it's a
+// private one, its ID and secret token must be passed along. With partial set,
+// a failed batch doesn't fail the whole call and its IDs are reported as
+// missing, unless every batch failed, ctx is done or the circuit breaker is
+// open.
+func (s *SoundCloudClient) getTracks(ctx context.Context, ids []int64, playlist *Playlist, partial bool) ([]Track, []int64, error) {
+	unique := slices.Compact(slices.Sorted(slices.Values(ids)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		byID     = make(map[int64]Track, len(unique))
+		firstErr error
+		batches  int
+		failed   []error
+	)
+	sem := make(chan struct{}, tracksConcurrency)
+	for batch := range slices.Chunk(unique, tracksBatchSize) {
+		batches++
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fetched, err := s.getTracksBatch(ctx, batch, playlist)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if partial && ctx.Err() == nil && !errors.Is(err, ErrUpstreamUnavailable) {
+					s.logInfo("Fetching %d tracks failed: %v", len(batch), err)
+					failed = append(failed, err)
+					return
+				}
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for _, t := range fetched {
+				byID[t.ID] = t
+			}
+		})
+	}
+	wg.Wait()
+	if firstErr == nil && len(failed) > 0 && len(failed) == batches {
+		firstErr = failed[0]
+	}
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	tracks := make([]Track, 0, len(ids))
+	var missing []int64
+	for _, id := range ids {
+		if t, ok := byID[id]; ok {
+			tracks = append(tracks, t)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return tracks, missing, nil
+}
+
+func (s *SoundCloudClient) getTracksBatch(ctx context.Context, ids []int64, playlist *Playlist) ([]Track, error) {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	query := url.Values{"ids": {strings.Join(parts, ",")}}
+	if playlist != nil && playlist.SecretToken != "" {
+		query.Set("playlistId", strconv.FormatInt(playlist.ID, 10))
+		query.Set("playlistSecretToken", playlist.SecretToken)
+	}
+
+	var batch []Track
+	if err := s.getJSON(ctx, "/tracks", query, &batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetStreamURLByID is GetStreamURL for a track ID.
+func (s *SoundCloudClient) GetStreamURLByID(ctx context.Context, id int64, opts StreamOptions) (*StreamResult, error) {
+	track, err := s.GetTrackByID(ctx, id)
+	if err != nil {
+		return nil, Classify(err, "TRACK_NOT_FOUND", "Track not found or unavailable")
+	}
+	return s.StreamTrack(ctx, track, opts)
+}
+
+// commentsQuery asks for a flat list of comments, replies included.
+var commentsQuery = url.Values{"threaded": {"0"}, "filter_replies": {"0"}}
+
+// GetTrackComments returns one page of a track's comments, newest first.
+func (s *SoundCloudClient) GetTrackComments(ctx context.Context, trackID int64, opts PageOptions) (*Page[Comment], error) {
+	return getPage[Comment](ctx, s, trackPath(trackID, "comments"), commentsQuery, opts)
+}
+
+// TrackComments iterates over all comments of a track.
+func (s *SoundCloudClient) TrackComments(ctx context.Context, trackID int64, opts IterOptions) iter.Seq2[Comment, error] {
+	return Paginate[Comment](ctx, s, trackPath(trackID, "comments"), commentsQuery, opts)
+}
+
+// GetRelatedTracks returns tracks SoundCloud considers similar to a track.
+func (s *SoundCloudClient) GetRelatedTracks(ctx context.Context, trackID int64, opts PageOptions) (*Page[Track], error) {
+	return getPage[Track](ctx, s, trackPath(trackID, "related"), nil, opts)
+}
+
+// GetStationTracks returns the tracks of a station, e.g. a track's
+// StationURN.
+func (s *SoundCloudClient) GetStationTracks(ctx context.Context, stationURN string, opts PageOptions) (*Page[Track], error) {
+	return getPage[Track](ctx, s, "/stations/"+url.PathEscape(stationURN)+"/tracks", nil, opts)
+}
+
+func trackPath(trackID int64, collection string) string {
+	return "/tracks/" + strconv.FormatInt(trackID, 10) + "/" + collection
+}
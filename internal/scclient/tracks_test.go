@@ -0,0 +1,137 @@
+package scclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseTrackID(t *testing.T) {
+	for in, want := range map[string]int64{"123": 123, " soundcloud:tracks:456 ": 456} {
+		got, err := ParseTrackID(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseTrackID(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "abc", "-1", "0", "soundcloud:users:1"} {
+		if _, err := ParseTrackID(in); err == nil {
+			t.Fatalf("ParseTrackID(%q) returned no error", in)
+		}
+	}
+}
+
+func TestTrackIDFromURL(t *testing.T) {
+	tests := map[string]int64{
+		"https://api.soundcloud.com/tracks/123":    123,
+		"https://api-v2.soundcloud.com/tracks/42/": 42,
+		"https://soundcloud.com/tracks/123":        0,
+		"https://api.soundcloud.com/users/123":     0,
+	}
+	for in, want := range tests {
+		got, ok := TrackIDFromURL(in)
+		if got != want || ok != (want != 0) {
+			t.Fatalf("TrackIDFromURL(%q) = %d, %v, want %d", in, got, ok, want)
+		}
+	}
+}
+
+func TestGetStreamURLByID_SkipsResolve(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected /resolve request")
+	})
+	mux.HandleFunc("/tracks/7", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"id":    int64(7),
+			"kind":  "track",
+			"title": "Song",
+			"media": map[string]interface{}{
+				"transcodings": []interface{}{
+					map[string]interface{}{
+						"url":    "https://api-v2.soundcloud.com/media/soundcloud:tracks:7/abc/stream/progressive",
+						"format": map[string]interface{}{"protocol": "progressive", "mime_type": "audio/mpeg"},
+					},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/media/soundcloud:tracks:7/abc/stream/progressive", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"url": "https://cf-media.sndcdn.com/abc.mp3"})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	result, err := c.GetStreamURLByID(context.Background(), 7, StreamOptions{})
+	if err != nil {
+		t.Fatalf("GetStreamURLByID returned error: %v", err)
+	}
+	if result.Track.ID != 7 || result.URL != "https://cf-media.sndcdn.com/abc.mp3" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestGetTracks_BatchesAndKeepsInputOrder(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+
+		var tracks []map[string]interface{}
+		for _, id := range ids {
+			if id == "13" {
+				continue
+			}
+			n, _ := strconv.ParseInt(id, 10, 64)
+			tracks = append(tracks, map[string]interface{}{"id": n, "kind": "track", "title": "T" + id})
+		}
+		writeJSON(w, tracks)
+	}))
+
+	var ids []int64
+	for i := int64(120); i >= 1; i-- {
+		ids = append(ids, i)
+	}
+	ids = append(ids, 5)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	tracks, missing, err := c.GetTracks(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetTracks returned error: %v", err)
+	}
+
+	if len(batches) != 3 {
+		t.Fatalf("batches = %d, want 3", len(batches))
+	}
+	for _, b := range batches {
+		if len(b) > tracksBatchSize {
+			t.Fatalf("batch of %d IDs, want at most %d", len(b), tracksBatchSize)
+		}
+	}
+	if len(missing) != 1 || missing[0] != 13 {
+		t.Fatalf("missing = %v, want [13]", missing)
+	}
+	if len(tracks) != len(ids)-1 {
+		t.Fatalf("len(tracks) = %d, want %d", len(tracks), len(ids)-1)
+	}
+	if tracks[0].ID != 120 || tracks[len(tracks)-2].ID != 1 || tracks[len(tracks)-1].ID != 5 {
+		t.Fatalf("tracks not in input order: first %d, last %d", tracks[0].ID, tracks[len(tracks)-1].ID)
+	}
+}
+
+func TestGetTracks_ReturnsBatchError(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	if _, _, err := c.GetTracks(context.Background(), []int64{1, 2}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,130 @@
+package scclient
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Supported values for StreamOptions, in default order of preference.
+// Encrypted HLS variants are not listed since clients can't play them without
+// DRM keys.
+var (
+	streamFormats   = []string{"mp3", "aac", "opus"}
+	streamProtocols = []string{"progressive", "hls"}
+	streamQualities = []string{"hq", "sq"}
+)
+
+// StreamOptions narrows down which transcoding GetStreamURL picks. Each field
+// is an ordered fallback list, most preferred value first; an empty list
+// accepts any supported value.
+type StreamOptions struct {
+	Formats   []string
+	Protocols []string
+	Qualities []string
+}
+
+// ParseStreamOptions builds StreamOptions from comma-separated lists such as
+// "aac,mp3". Unknown values are rejected with an INVALID_STREAM_OPTIONS error.
+func ParseStreamOptions(formats, protocols, qualities string) (StreamOptions, error) {
+	var opts StreamOptions
+	var err error
+	if opts.Formats, err = parseOptionList("format", formats, streamFormats); err != nil {
+		return StreamOptions{}, err
+	}
+	if opts.Protocols, err = parseOptionList("protocol", protocols, streamProtocols); err != nil {
+		return StreamOptions{}, err
+	}
+	if opts.Qualities, err = parseOptionList("quality", qualities, streamQualities); err != nil {
+		return StreamOptions{}, err
+	}
+	return opts, nil
+}
+
+func parseOptionList(name, raw string, allowed []string) ([]string, error) {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" || slices.Contains(values, v) {
+			continue
+		}
+		if !slices.Contains(allowed, v) {
+			return nil, newError("INVALID_STREAM_OPTIONS", "Unsupported "+name+" '"+v+"' (allowed: "+strings.Join(allowed, ", ")+")")
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// FormatName returns the short codec name of the transcoding (mp3, aac or
+// opus), derived from its MIME type and falling back to the preset prefix.
+func (t *Transcoding) FormatName() string {
+	mime := strings.ToLower(t.Format.MimeType)
+	switch {
+	case strings.Contains(mime, "opus"):
+		return "opus"
+	case strings.Contains(mime, "mp4a"), strings.Contains(mime, "audio/mp4"), strings.Contains(mime, "aac"):
+		return "aac"
+	case strings.Contains(mime, "audio/mpeg"):
+		return "mp3"
+	}
+	name, _, _ := strings.Cut(t.Preset, "_")
+	return name
+}
+
+// selectTranscoding picks the best transcoding for opts. Full-length streams
+// always win over previews; ties are broken by format, then protocol, then
+// quality preference. Returns nil when nothing matches.
+func selectTranscoding(transcodings []Transcoding, opts StreamOptions) *Transcoding {
+	formats := opts.Formats
+	protocols := opts.Protocols
+	if len(protocols) == 0 {
+		protocols = streamProtocols
+	}
+	qualities := opts.Qualities
+
+	type candidate struct {
+		t    *Transcoding
+		rank [4]int
+	}
+	var candidates []candidate
+	for i := range transcodings {
+		t := &transcodings[i]
+		if t.URL == "" {
+			continue
+		}
+		protocolRank := slices.Index(protocols, t.Format.Protocol)
+		formatRank := rankOf(formats, t.FormatName())
+		qualityRank := rankOf(qualities, t.Quality)
+		if protocolRank < 0 || formatRank < 0 || qualityRank < 0 {
+			continue
+		}
+		snippetRank := 0
+		if t.Snippet {
+			snippetRank = 1
+		}
+		candidates = append(candidates, candidate{t: t, rank: [4]int{snippetRank, formatRank, protocolRank, qualityRank}})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return slices.Compare(candidates[i].rank[:], candidates[j].rank[:]) < 0
+	})
+	return candidates[0].t
+}
+
+// rankOf returns the position of v in prefs, 0 when prefs is empty (anything
+// goes) and -1 when v isn't acceptable.
+func rankOf(prefs []string, v string) int {
+	if len(prefs) == 0 {
+		return 0
+	}
+	return slices.Index(prefs, v)
+}
+
+// Playable reports whether GetStreamURL is able to hand out this transcoding.
+func (t *Transcoding) Playable() bool {
+	return t.URL != "" && slices.Contains(streamProtocols, t.Format.Protocol)
+}
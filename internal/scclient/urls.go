@@ -0,0 +1,173 @@
+package scclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxShortLinkHops bounds the redirects followed when expanding a short link.
+const maxShortLinkHops = 5
+
+// shortLinkHosts serve redirects to soundcloud.com, e.g. the share links of
+// the mobile app.
+var shortLinkHosts = map[string]bool{
+	"on.soundcloud.com":     true,
+	"soundcloud.app.goo.gl": true,
+}
+
+// soundcloudHosts are aliases of soundcloud.com.
+var soundcloudHosts = map[string]bool{
+	"soundcloud.com":        true,
+	"www.soundcloud.com":    true,
+	"m.soundcloud.com":      true,
+	"mobile.soundcloud.com": true,
+}
+
+// secretTokenRe matches the last path segment of a private share link, e.g.
+// https://soundcloud.com/artist/track/s-AbCdE.
+var secretTokenRe = regexp.MustCompile(`^s-[A-Za-z0-9]+$`)
+
+// SplitSecretToken splits the secret token off a private share link and
+// returns the public part of the URL and the token. URLs without one are
+// returned unchanged with an empty token.
+func SplitSecretToken(rawURL string) (string, string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, ""
+	}
+	// The token follows a track or set slug: /artist/track/s-xyz or
+	// /artist/sets/name/s-xyz. /artist/s-xyz is a plain track and
+	// /artist/sets/s-xyz a plain set.
+	path := strings.TrimRight(u.Path, "/")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch {
+	case len(segments) == 3 && segments[1] != "sets":
+	case len(segments) == 4 && segments[1] == "sets":
+	default:
+		return rawURL, ""
+	}
+	if !secretTokenRe.MatchString(segments[len(segments)-1]) {
+		return rawURL, ""
+	}
+	i := strings.LastIndex(path, "/")
+	token := path[i+1:]
+	u.Path = path[:i]
+	u.RawPath = ""
+	return u.String(), token
+}
+
+// apiHosts serve the public and the web API, where tracks live at
+// /tracks/{id}.
+var apiHosts = map[string]bool{
+	"api.soundcloud.com":    true,
+	"api-v2.soundcloud.com": true,
+}
+
+// TrackIDFromURL extracts the track ID from API URLs such as
+// https://api.soundcloud.com/tracks/123.
+func TrackIDFromURL(rawURL string) (int64, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !apiHosts[strings.ToLower(u.Host)] {
+		return 0, false
+	}
+	rest, ok := strings.CutPrefix(strings.TrimRight(u.Path, "/"), "/tracks/")
+	if !ok {
+		return 0, false
+	}
+	id, err := ParseTrackID(rest)
+	return id, err == nil
+}
+
+// IsPrivate reports whether the track is only reachable through a secret
+// share link.
+func (t *Track) IsPrivate() bool {
+	return t.SecretToken != "" || t.Sharing == "private"
+}
+
+// NormalizeURL turns a pasted SoundCloud link into a canonical
+// https://soundcloud.com URL: short links are expanded, mobile and www hosts
+// are rewritten and tracking parameters are dropped. URLs on other hosts are
+// returned unchanged apart from the scheme.
+func (s *SoundCloudClient) NormalizeURL(ctx context.Context, rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", newError("INVALID_URL", "Invalid SoundCloud URL format")
+	}
+	u.Host = strings.ToLower(u.Host)
+
+	for hops := 0; shortLinkHosts[u.Host]; hops++ {
+		if hops == maxShortLinkHops {
+			return "", newError("INVALID_URL", "Short link redirects too many times")
+		}
+		if u, err = s.followShortLink(ctx, u); err != nil {
+			return "", err
+		}
+		u.Host = strings.ToLower(u.Host)
+	}
+
+	canonicalize(u)
+	return u.String(), nil
+}
+
+// followShortLink returns the target of the redirect at u.
+func (s *SoundCloudClient) followShortLink(ctx context.Context, u *url.URL) (*url.URL, error) {
+	client := *s.httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	req.Header.Set("User-Agent", s.userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, wrapError("NETWORK_ERROR", "Network error: "+err.Error(), fmt.Errorf("%w: %w", ErrUpstream, err))
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	loc := resp.Header.Get("Location")
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 || loc == "" {
+		return nil, wrapError("INVALID_URL", "Short link could not be resolved",
+			&UpstreamError{Op: "GET " + u.String(), StatusCode: resp.StatusCode})
+	}
+	target, err := u.Parse(loc)
+	if err != nil {
+		return nil, newError("INVALID_URL", "Short link redirects to an invalid URL")
+	}
+	s.logDebug("Short link %s redirects to %s", u, target)
+	return target, nil
+}
+
+// canonicalize rewrites SoundCloud URLs to https://soundcloud.com and drops
+// tracking parameters and the fragment.
+func canonicalize(u *url.URL) {
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	if !soundcloudHosts[u.Host] {
+		return
+	}
+	u.Host = "soundcloud.com"
+	u.Fragment = ""
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	q := u.Query()
+	for key := range q {
+		if key == "si" || strings.HasPrefix(key, "utm_") {
+			q.Del(key)
+		}
+	}
+	u.RawQuery = q.Encode()
+}
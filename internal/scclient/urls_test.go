@@ -0,0 +1,108 @@
+package scclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL_Canonicalizes(t *testing.T) {
+	c := New("", "cid", time.Second)
+
+	tests := map[string]string{
+		"https://soundcloud.com/artist/track":                               "https://soundcloud.com/artist/track",
+		"http://www.soundcloud.com/artist/track/":                           "https://soundcloud.com/artist/track",
+		"https://m.soundcloud.com/artist/track?si=abc&utm_source=clipboard": "https://soundcloud.com/artist/track",
+		"soundcloud.com/artist/sets/album?in=x#t=1:00":                      "https://soundcloud.com/artist/sets/album?in=x",
+		" https://SoundCloud.com/artist ":                                   "https://soundcloud.com/artist",
+		"https://example.com/artist/track?si=abc":                           "https://example.com/artist/track?si=abc",
+	}
+	for in, want := range tests {
+		got, err := c.NormalizeURL(context.Background(), in)
+		if err != nil {
+			t.Fatalf("NormalizeURL(%q) returned error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("NormalizeURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeURL_FollowsShortLinks(t *testing.T) {
+	var hops int
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		hops++
+		resp := &http.Response{StatusCode: http.StatusFound, Header: http.Header{}, Body: http.NoBody, Request: r}
+		switch r.URL.Host {
+		case "soundcloud.app.goo.gl":
+			resp.Header.Set("Location", "https://on.soundcloud.com/abc")
+		case "on.soundcloud.com":
+			resp.Header.Set("Location", "https://m.soundcloud.com/artist/track?utm_medium=text&si=123")
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+		}
+		return resp, nil
+	})
+
+	c := New("", "cid", time.Second, WithTransport(rt))
+	got, err := c.NormalizeURL(context.Background(), "https://soundcloud.app.goo.gl/xyz")
+	if err != nil {
+		t.Fatalf("NormalizeURL returned error: %v", err)
+	}
+	if got != "https://soundcloud.com/artist/track" {
+		t.Fatalf("NormalizeURL = %q", got)
+	}
+	if hops != 2 {
+		t.Fatalf("hops = %d, want 2", hops)
+	}
+}
+
+func TestNormalizeURL_LimitsHops(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		next := &url.URL{Scheme: "https", Host: "on.soundcloud.com", Path: r.URL.Path + "x"}
+		return &http.Response{StatusCode: http.StatusMovedPermanently, Header: http.Header{"Location": {next.String()}}, Body: http.NoBody, Request: r}, nil
+	})
+
+	c := New("", "cid", time.Second, WithTransport(rt))
+	_, err := c.NormalizeURL(context.Background(), "https://on.soundcloud.com/loop")
+	var scErr *Error
+	if !errors.As(err, &scErr) || scErr.Code != "INVALID_URL" {
+		t.Fatalf("err = %v, want INVALID_URL", err)
+	}
+}
+
+func TestNormalizeURL_DeadShortLinkIsNotFound(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: r}, nil
+	})
+
+	c := New("", "cid", time.Second, WithTransport(rt))
+	_, err := c.NormalizeURL(context.Background(), "https://on.soundcloud.com/gone")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSplitSecretToken(t *testing.T) {
+	tests := []struct {
+		in, base, token string
+	}{
+		{"https://soundcloud.com/artist/track/s-AbCdE", "https://soundcloud.com/artist/track", "s-AbCdE"},
+		{"https://soundcloud.com/artist/sets/demos/s-x1Y2/", "https://soundcloud.com/artist/sets/demos", "s-x1Y2"},
+		{"https://soundcloud.com/artist/s-club", "https://soundcloud.com/artist/s-club", ""},
+		{"https://soundcloud.com/artist/sets/s-club", "https://soundcloud.com/artist/sets/s-club", ""},
+		{"https://soundcloud.com/artist/sets/s-club/s-x1Y2", "https://soundcloud.com/artist/sets/s-club", "s-x1Y2"},
+		{"https://soundcloud.com/artist/track/s-AbCdE/extra", "https://soundcloud.com/artist/track/s-AbCdE/extra", ""},
+		{"https://soundcloud.com/artist/track", "https://soundcloud.com/artist/track", ""},
+		{"https://soundcloud.com/artist/track/s-", "https://soundcloud.com/artist/track/s-", ""},
+	}
+	for _, tt := range tests {
+		base, token := SplitSecretToken(tt.in)
+		if base != tt.base || token != tt.token {
+			t.Fatalf("SplitSecretToken(%q) = %q, %q, want %q, %q", tt.in, base, token, tt.base, tt.token)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package scclient
+
+import (
+	"context"
+	"iter"
+	"strconv"
+)
+
+func (s *SoundCloudClient) ResolveUser(ctx context.Context, profileURL string) (*User, error) {
+	var user User
+	if err := s.resolve(ctx, profileURL, &user); err != nil {
+		return nil, err
+	}
+	if user.Kind != "user" {
+		return nil, newError("NOT_A_USER", "URL points to a "+user.Kind+", not a user")
+	}
+	return &user, nil
+}
+
+// GetUserTracks returns one page of the tracks uploaded by a user, newest first.
+func (s *SoundCloudClient) GetUserTracks(ctx context.Context, userID int64, opts PageOptions) (*Page[Track], error) {
+	return getPage[Track](ctx, s, userPath(userID, "tracks"), nil, opts)
+}
+
+// UserTracks iterates over all tracks uploaded by a user.
+func (s *SoundCloudClient) UserTracks(ctx context.Context, userID int64, opts IterOptions) iter.Seq2[Track, error] {
+	return Paginate[Track](ctx, s, userPath(userID, "tracks"), nil, opts)
+}
+
+// GetUserLikes returns one page of the tracks and playlists a user liked, most
+// recent first.
+func (s *SoundCloudClient) GetUserLikes(ctx context.Context, userID int64, opts PageOptions) (*Page[Like], error) {
+	return getPage[Like](ctx, s, userPath(userID, "likes"), nil, opts)
+}
+
+// UserLikes iterates over all likes of a user.
+func (s *SoundCloudClient) UserLikes(ctx context.Context, userID int64, opts IterOptions) iter.Seq2[Like, error] {
+	return Paginate[Like](ctx, s, userPath(userID, "likes"), nil, opts)
+}
+
+// GetUserReposts returns one page of a user's reposts, most recent first.
+func (s *SoundCloudClient) GetUserReposts(ctx context.Context, userID int64, opts PageOptions) (*Page[Repost], error) {
+	return getPage[Repost](ctx, s, "/stream"+userPath(userID, "reposts"), nil, opts)
+}
+
+// UserReposts iterates over all reposts of a user.
+func (s *SoundCloudClient) UserReposts(ctx context.Context, userID int64, opts IterOptions) iter.Seq2[Repost, error] {
+	return Paginate[Repost](ctx, s, "/stream"+userPath(userID, "reposts"), nil, opts)
+}
+
+func userPath(userID int64, collection string) string {
+	return "/users/" + strconv.FormatInt(userID, 10) + "/" + collection
+}
@@ -0,0 +1,36 @@
+package scclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetUserLikes_DecodesTracksAndPlaylists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/5/likes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"collection": []interface{}{
+				map[string]interface{}{"created_at": "2025-01-02T00:00:00Z", "kind": "like", "track": map[string]interface{}{"id": 1, "title": "Song"}},
+				map[string]interface{}{"created_at": "2025-01-01T00:00:00Z", "kind": "like", "playlist": map[string]interface{}{"id": 2, "title": "Set"}},
+			},
+		})
+	})
+	srv := newTestServer(t, mux)
+
+	c := New("", "cid", time.Second, WithBaseURL(srv.URL))
+	page, err := c.GetUserLikes(context.Background(), 5, PageOptions{})
+	if err != nil {
+		t.Fatalf("GetUserLikes returned error: %v", err)
+	}
+	if len(page.Collection) != 2 {
+		t.Fatalf("len(Collection) = %d, want 2", len(page.Collection))
+	}
+	if page.Collection[0].Track == nil || page.Collection[0].Track.Title != "Song" {
+		t.Fatalf("first like should be a track: %+v", page.Collection[0])
+	}
+	if page.Collection[1].Playlist == nil || page.Collection[1].CreatedAt != "2025-01-01T00:00:00Z" {
+		t.Fatalf("second like should be a playlist: %+v", page.Collection[1])
+	}
+}
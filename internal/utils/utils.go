@@ -2,10 +2,12 @@ package utils
 
 import (
 	"encoding/json"
+	"io"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func GetClientID(r *http.Request) string {
@@ -40,8 +42,9 @@ func ValidateSoundCloudURL(raw string, maxLen int) (bool, string) {
 		return false, "Invalid SoundCloud URL format"
 	}
 
-	parts := strings.Split(strings.Trim(raw, "/"), "/")
-	if len(parts) < 4 || parts[2] == "" {
+	path, _, _ := strings.Cut(strings.TrimPrefix(raw, "https://soundcloud.com/"), "?")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if parts[0] == "" {
 		return false, "Invalid SoundCloud track URL format"
 	}
 
@@ -55,14 +58,61 @@ func IfString(s string, otherwise string) string {
 	return s
 }
 
-func DeepCopyMap(m map[string]interface{}) map[string]interface{} {
-	b, err := json.Marshal(m)
-	if err != nil {
-		return m
+// JSONStream writes a response as a sequence of JSON records, either as
+// newline-delimited JSON or as Server-Sent Events, flushing each record so the
+// client sees it right away.
+type JSONStream struct {
+	w            http.ResponseWriter
+	rc           *http.ResponseController
+	sse          bool
+	writeTimeout time.Duration
+}
+
+// NewJSONStream starts a streaming response with status. Records are sent as
+// Server-Sent Events if sse is set and as NDJSON otherwise. If writeTimeout is
+// positive, the connection's write deadline is pushed back by that much before
+// every record, so long streams aren't cut off by the server's WriteTimeout.
+func NewJSONStream(w http.ResponseWriter, status int, sse bool, writeTimeout time.Duration) *JSONStream {
+	s := &JSONStream{w: w, rc: http.NewResponseController(w), sse: sse, writeTimeout: writeTimeout}
+	s.extendDeadline()
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
 	}
-	var out map[string]interface{}
-	if err := json.Unmarshal(b, &out); err != nil {
-		return m
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(status)
+	_ = s.rc.Flush()
+	return s
+}
+
+// Write sends v as one record and flushes it. event names the SSE event and is
+// ignored for NDJSON. An error means the client can't be written to anymore.
+func (s *JSONStream) Write(event string, v interface{}) error {
+	s.extendDeadline()
+	if s.sse {
+		if _, err := io.WriteString(s.w, "event: "+event+"\ndata: "); err != nil {
+			return err
+		}
+	}
+	enc := json.NewEncoder(s.w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	if s.sse {
+		if _, err := io.WriteString(s.w, "\n"); err != nil {
+			return err
+		}
+	}
+	return s.rc.Flush()
+}
+
+func (s *JSONStream) extendDeadline() {
+	if s.writeTimeout > 0 {
+		// Not every ResponseWriter supports deadlines; the server's
+		// WriteTimeout applies then.
+		_ = s.rc.SetWriteDeadline(time.Now().Add(s.writeTimeout))
 	}
-	return out
 }
@@ -4,6 +4,11 @@ import "time"
 
 type StreamRequest struct {
 	TrackURL string `json:"track_url"`
+	ID       int64  `json:"id,omitempty"`
+	URN      string `json:"urn,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Quality  string `json:"quality,omitempty"`
 }
 
 type RateInfo struct {
@@ -12,22 +17,217 @@ type RateInfo struct {
 }
 
 type HealthResponse struct {
-	Status     string `json:"status"`
-	Service    string `json:"service"`
-	Timestamp  string `json:"timestamp"`
-	Version    string `json:"version"`
-	TokenError string `json:"token_error,omitempty"`
+	Status      string             `json:"status"`
+	Service     string             `json:"service"`
+	Timestamp   string             `json:"timestamp"`
+	Version     string             `json:"version"`
+	TokenError  string             `json:"token_error,omitempty"`
+	Upstream    *UpstreamHealth    `json:"upstream,omitempty"`
+	Credentials []CredentialHealth `json:"credentials,omitempty"`
+}
+
+// UpstreamHealth is the state of the circuit breaker around SoundCloud.
+type UpstreamHealth struct {
+	Breaker   string `json:"breaker"`
+	Requests  int    `json:"requests"`
+	Failures  int    `json:"failures"`
+	OpenUntil string `json:"open_until,omitempty"`
+}
+
+// CredentialHealth is the state of one configured credential. ClientID is
+// masked.
+type CredentialHealth struct {
+	Index         int    `json:"index"`
+	ClientID      string `json:"client_id"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	LastStatus    int    `json:"last_status,omitempty"`
+	CooldownUntil string `json:"cooldown_until,omitempty"`
+	Requests      int64  `json:"requests"`
 }
 
 type StreamResponse struct {
-	StreamURL interface{}            `json:"stream_url"`
-	Error     interface{}            `json:"error"`
-	ErrorCode interface{}            `json:"error_code"`
-	TrackInfo map[string]interface{} `json:"track_info"`
-	CacheInfo map[string]interface{} `json:"cache_info"`
+	StreamURL interface{} `json:"stream_url"`
+	Error     interface{} `json:"error"`
+	ErrorCode interface{} `json:"error_code"`
+	Protocol  string      `json:"protocol,omitempty"`
+	Format    string      `json:"format,omitempty"`
+	Quality   string      `json:"quality,omitempty"`
+	MimeType  string      `json:"mime_type,omitempty"`
+	Private   bool        `json:"private,omitempty"`
+	TrackInfo *TrackInfo  `json:"track_info,omitempty"`
+	CacheInfo *CacheInfo  `json:"cache_info,omitempty"`
+}
+
+type TrackInfo struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	Artist       string `json:"artist"`
+	Duration     int64  `json:"duration"`
+	PermalinkURL string `json:"permalink_url"`
+	ArtworkURL   string `json:"artwork_url"`
+	Genre        string `json:"genre"`
+	ReleaseDate  string `json:"release_date"`
+}
+
+type CacheInfo struct {
+	Timestamp  string `json:"timestamp"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+type TranscodingsResponse struct {
+	TrackID      int64             `json:"track_id"`
+	Policy       string            `json:"policy"`
+	TrackInfo    *TrackInfo        `json:"track_info"`
+	Transcodings []TranscodingInfo `json:"transcodings"`
+}
+
+type TranscodingInfo struct {
+	Protocol string `json:"protocol"`
+	MimeType string `json:"mime_type"`
+	Format   string `json:"format"`
+	Quality  string `json:"quality"`
+	Preset   string `json:"preset"`
+	Duration int64  `json:"duration"`
+	Snippet  bool   `json:"snippet"`
+	Legacy   bool   `json:"legacy"`
+	Playable bool   `json:"playable"`
+}
+
+type PlaylistInfo struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	Artist       string `json:"artist"`
+	PermalinkURL string `json:"permalink_url"`
+	ArtworkURL   string `json:"artwork_url"`
+	Duration     int64  `json:"duration"`
+	IsAlbum      bool   `json:"is_album"`
+	TrackCount   int    `json:"track_count"`
+}
+
+type PlaylistResponse struct {
+	PlaylistInfo
+	Description string          `json:"description"`
+	Tracks      []PlaylistTrack `json:"tracks"`
+}
+
+type PlaylistTrack struct {
+	Position  int             `json:"position"`
+	ID        int64           `json:"id"`
+	Available bool            `json:"available"`
+	TrackInfo *TrackInfo      `json:"track_info,omitempty"`
+	Stream    *StreamResponse `json:"stream,omitempty"`
+}
+
+type UserInfo struct {
+	ID              int64  `json:"id"`
+	Username        string `json:"username"`
+	FullName        string `json:"full_name"`
+	PermalinkURL    string `json:"permalink_url"`
+	AvatarURL       string `json:"avatar_url"`
+	City            string `json:"city"`
+	CountryCode     string `json:"country_code"`
+	Description     string `json:"description"`
+	FollowersCount  int64  `json:"followers_count"`
+	FollowingsCount int64  `json:"followings_count"`
+	TrackCount      int64  `json:"track_count"`
+	PlaylistCount   int64  `json:"playlist_count"`
+	LikesCount      int64  `json:"likes_count"`
+	Verified        bool   `json:"verified"`
+}
+
+type UserTracksResponse struct {
+	User       *UserInfo   `json:"user"`
+	Tracks     []TrackInfo `json:"tracks"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type UserItemsResponse struct {
+	User       *UserInfo  `json:"user"`
+	Items      []UserItem `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// UserItem is a liked or reposted track or playlist. CreatedAt is when it was
+// liked or reposted.
+type UserItem struct {
+	Kind      string        `json:"kind"`
+	CreatedAt string        `json:"created_at"`
+	Track     *TrackInfo    `json:"track,omitempty"`
+	Playlist  *PlaylistInfo `json:"playlist,omitempty"`
+}
+
+type CommentsResponse struct {
+	TrackInfo  *TrackInfo    `json:"track_info"`
+	Comments   []CommentInfo `json:"comments"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// CommentInfo is a track comment. Timestamp is the position on the waveform in
+// milliseconds, or null when the comment isn't attached to one.
+type CommentInfo struct {
+	ID        int64  `json:"id"`
+	Body      string `json:"body"`
+	Timestamp *int64 `json:"timestamp"`
+	CreatedAt string `json:"created_at"`
+	Username  string `json:"username"`
+	UserID    int64  `json:"user_id"`
+}
+
+type RelatedResponse struct {
+	Source string         `json:"source"`
+	Seed   *TrackInfo     `json:"seed"`
+	Tracks []RelatedTrack `json:"tracks"`
+}
+
+type RelatedTrack struct {
+	TrackInfo
+	Stream *StreamResponse `json:"stream,omitempty"`
+}
+
+type SearchResponse struct {
+	Query        string         `json:"query"`
+	Type         string         `json:"type"`
+	TotalResults int64          `json:"total_results"`
+	Tracks       []TrackInfo    `json:"tracks,omitempty"`
+	Users        []UserInfo     `json:"users,omitempty"`
+	Playlists    []PlaylistInfo `json:"playlists,omitempty"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
 }
 
 type RateLimitResponse struct {
 	Error   string                 `json:"error"`
 	Details map[string]interface{} `json:"details"`
 }
+
+// BatchStreamRequest asks for the stream URLs of several tracks. Format,
+// Protocol and Quality apply to items that don't set their own.
+type BatchStreamRequest struct {
+	Tracks   []StreamRequest `json:"tracks"`
+	Format   string          `json:"format,omitempty"`
+	Protocol string          `json:"protocol,omitempty"`
+	Quality  string          `json:"quality,omitempty"`
+}
+
+type BatchStreamResponse struct {
+	Results   []BatchStreamResult `json:"results"`
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// BatchStreamSummary is the last record of a streamed batch. Done tells it
+// apart from the results before it.
+type BatchStreamSummary struct {
+	Done      bool `json:"done"`
+	Total     int  `json:"total"`
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+}
+
+// BatchStreamResult is the outcome for the track at Index in the request.
+type BatchStreamResult struct {
+	Index int    `json:"index"`
+	Input string `json:"input"`
+	*StreamResponse
+}